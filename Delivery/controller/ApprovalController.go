@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ApprovalController struct {
+	ApprovalUsecase Usecases.ApprovalUsecase
+}
+
+// NewApprovalController creates a new instance of ApprovalController
+func NewApprovalController(approvalUsecase Usecases.ApprovalUsecase) *ApprovalController {
+	return &ApprovalController{
+		ApprovalUsecase: approvalUsecase,
+	}
+}
+
+// GetPolicies handles listing the approval policy of every currency
+func (ac *ApprovalController) GetPolicies(c *gin.Context) {
+	policies, err := ac.ApprovalUsecase.GetPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+// SetPolicy handles creating or replacing the approval policy of a currency
+func (ac *ApprovalController) SetPolicy(c *gin.Context) {
+	var input Domain.ApprovalPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	policy, err := ac.ApprovalUsecase.SetPolicy(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policy": policy})
+}
+
+// DeletePolicy handles removing the approval policy of a currency
+func (ac *ApprovalController) DeletePolicy(c *gin.Context) {
+	deletedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = ac.ApprovalUsecase.DeletePolicy(c.Param("currency"), deletedBy)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrApprovalPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Approval policy deleted successfully"})
+}
+
+// GetLimits handles listing the approval limits of every role and user
+func (ac *ApprovalController) GetLimits(c *gin.Context) {
+	limits, err := ac.ApprovalUsecase.GetLimits()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"limits": limits})
+}
+
+// SetLimit handles creating or replacing the approval limit of a role or user
+func (ac *ApprovalController) SetLimit(c *gin.Context) {
+	var input Domain.ApprovalLimitInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	limit, err := ac.ApprovalUsecase.SetLimit(input)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"limit": limit})
+}
+
+// DeleteLimit handles removing an approval limit
+func (ac *ApprovalController) DeleteLimit(c *gin.Context) {
+	deletedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = ac.ApprovalUsecase.DeleteLimit(c.Param("id"), deletedBy)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrApprovalLimitNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Approval limit deleted successfully"})
+}
+
+// ViewEscalations handles listing the escalated loans the requester may approve
+func (ac *ApprovalController) ViewEscalations(c *gin.Context) {
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	loans, err := ac.ApprovalUsecase.ViewEscalations(requester)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"loans": loans})
+}
+
+// ViewApprovals handles retrieving the recommendations and decisions taken on a loan
+func (ac *ApprovalController) ViewApprovals(c *gin.Context) {
+	steps, err := ac.ApprovalUsecase.ViewApprovals(c.Param("id"))
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approvals": steps})
+}
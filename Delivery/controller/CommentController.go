@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentController struct {
+	CommentUsecase Usecases.CommentUsecase
+}
+
+// NewCommentController creates a new instance of CommentController
+func NewCommentController(commentUsecase Usecases.CommentUsecase) *CommentController {
+	return &CommentController{
+		CommentUsecase: commentUsecase,
+	}
+}
+
+// ViewComments handles retrieving the comment thread of a loan
+func (cc *CommentController) ViewComments(c *gin.Context) {
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	comments, err := cc.CommentUsecase.ViewComments(c.Param("id"), requester)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments})
+}
+
+// AddComment handles posting an internal note or a borrower message on a loan
+func (cc *CommentController) AddComment(c *gin.Context) {
+	var input Domain.LoanCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	comment, err := cc.CommentUsecase.AddComment(c.Param("id"), input, requester)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"comment": comment})
+}
+
+// EditComment handles the author changing the body of a comment
+func (cc *CommentController) EditComment(c *gin.Context) {
+	var input Domain.LoanCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	comment, err := cc.CommentUsecase.EditComment(c.Param("id"), c.Param("comment_id"), input, requester)
+	if err != nil {
+		c.JSON(commentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comment": comment})
+}
+
+// commentErrorStatus maps comment thread errors to HTTP status codes
+func commentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, Usecases.ErrLoanNotFound), errors.Is(err, Usecases.ErrCommentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, Usecases.ErrNotCommentAuthor):
+		return http.StatusForbidden
+	case errors.Is(err, Usecases.ErrUserNotFound):
+		return http.StatusUnauthorized
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DisbursementController struct {
+	DisbursementUsecase Usecases.DisbursementUsecase
+}
+
+// NewDisbursementController creates a new instance of DisbursementController
+func NewDisbursementController(disbursementUsecase Usecases.DisbursementUsecase) *DisbursementController {
+	return &DisbursementController{
+		DisbursementUsecase: disbursementUsecase,
+	}
+}
+
+// DisburseLoan handles paying out an approved loan
+func (dc *DisbursementController) DisburseLoan(c *gin.Context) {
+	id := c.Param("id")
+
+	var input Domain.DisbursementInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	recordedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.RecordedBy = recordedBy
+
+	disbursement, err := dc.DisbursementUsecase.DisburseLoan(id, input)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"disbursement": disbursement})
+}
+
+// ViewDisbursement handles retrieving the disbursement of a loan
+func (dc *DisbursementController) ViewDisbursement(c *gin.Context) {
+	id := c.Param("id")
+
+	disbursement, err := dc.DisbursementUsecase.ViewDisbursement(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Disbursement not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"disbursement": disbursement})
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailTemplateController struct {
+	EmailTemplateUsecase Usecases.EmailTemplateUsecase
+	OutboxUsecase        Usecases.OutboxUsecase
+}
+
+// NewEmailTemplateController creates a new instance of EmailTemplateController
+func NewEmailTemplateController(emailTemplateUsecase Usecases.EmailTemplateUsecase, outboxUsecase Usecases.OutboxUsecase) *EmailTemplateController {
+	return &EmailTemplateController{
+		EmailTemplateUsecase: emailTemplateUsecase,
+		OutboxUsecase:        outboxUsecase,
+	}
+}
+
+// GetTemplates handles listing the email templates with their overrides
+func (ec *EmailTemplateController) GetTemplates(c *gin.Context) {
+	templates, err := ec.EmailTemplateUsecase.GetTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// SetTemplate handles overriding an email template for a locale
+func (ec *EmailTemplateController) SetTemplate(c *gin.Context) {
+	var input Domain.EmailTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	tmpl, err := ec.EmailTemplateUsecase.SetTemplate(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": tmpl})
+}
+
+// DeleteTemplate handles removing the override of an email template
+func (ec *EmailTemplateController) DeleteTemplate(c *gin.Context) {
+	deletedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = ec.EmailTemplateUsecase.DeleteTemplate(c.Param("name"), c.Param("locale"), deletedBy)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrEmailTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email template override not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email template override deleted successfully"})
+}
+
+// PreviewTemplate handles rendering an email with sample data without sending it
+func (ec *EmailTemplateController) PreviewTemplate(c *gin.Context) {
+	data := map[string]interface{}{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+
+	message, err := ec.EmailTemplateUsecase.Render(c.Param("name"), c.Param("locale"), data)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrEmailTemplateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email template not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email": message})
+}
+
+// GetOutbox handles listing the most recent emails in the outbox with their delivery status
+func (ec *EmailTemplateController) GetOutbox(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	messages, err := ec.OutboxUsecase.GetMessages(c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"emails": messages})
+}
+
+// GetOutboxMessage handles retrieving one email and its delivery status
+func (ec *EmailTemplateController) GetOutboxMessage(c *gin.Context) {
+	message, err := ec.OutboxUsecase.GetMessage(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, Usecases.ErrOutboxMessageNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email": message})
+}
+
+// RetryOutboxMessage handles requeueing a dead-lettered email
+func (ec *EmailTemplateController) RetryOutboxMessage(c *gin.Context) {
+	requestedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	message, err := ec.OutboxUsecase.RetryMessage(c.Param("id"), requestedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, Usecases.ErrOutboxMessageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email not found"})
+		case errors.Is(err, Usecases.ErrOutboxMessageNotDead):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email": message})
+}
@@ -4,15 +4,21 @@ import (
 	"Loan_Tracker/Domain"
 	Usecases "Loan_Tracker/Usecase"
 	"Loan_Tracker/infrastructure"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UpdateStatus struct {
-	Status string `json:"status" bson:"status"` // "pending", "approved", "rejected"
+	Status         string  `json:"status" bson:"status"` // Target status, e.g. "under_review", "approved", "rejected"
+	InterestRate   float64 `json:"interest_rate" bson:"interest_rate"`
+	InterestMethod string  `json:"interest_method" bson:"interest_method"` // "reducing_balance", "flat"
+	Comment        string  `json:"comment" bson:"comment"`
 }
 
 type LoanController struct {
@@ -27,9 +33,11 @@ func NewLoanController(loanUsecase Usecases.LoanUsecase) *LoanController {
 }
 
 type LoanInput struct {
-	Amount  float64 `json:"amount" bson:"amount"`
-	Term    int     `json:"term" bson:"term"` // In months
-	Purpose string  `json:"purpose" bson:"purpose"`
+	ProductID string       `json:"product_id" bson:"product_id"`
+	Amount    Domain.Money `json:"amount" bson:"amount"`
+	Term      int          `json:"term" bson:"term"` // In months
+	Purpose   string       `json:"purpose" bson:"purpose"`
+	Draft     bool         `json:"draft" bson:"draft"`
 }
 
 // CreateLoan handles loan creation
@@ -59,17 +67,25 @@ func (lc *LoanController) CreateLoan(c *gin.Context) {
 		return
 	}
 
+	productID, err := primitive.ObjectIDFromHex(inp.ProductID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
 	// Set the UserID in the input
 	input := Domain.LoanInput{
-		UserID:  userID,
-		Amount:  inp.Amount,
-		Term:    inp.Term,
-		Purpose: inp.Purpose,
+		UserID:    userID,
+		ProductID: productID,
+		Amount:    inp.Amount,
+		Term:      inp.Term,
+		Purpose:   inp.Purpose,
+		Draft:     inp.Draft,
 	}
 
 	loan, err := lc.LoanUsecase.ApplyForLoan(input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -80,27 +96,126 @@ func (lc *LoanController) CreateLoan(c *gin.Context) {
 func (lc *LoanController) ViewLoanStatus(c *gin.Context) {
 	id := c.Param("id")
 
-	loan, err := lc.LoanUsecase.ViewLoanStatus(id)
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	loan, err := lc.LoanUsecase.ViewLoanStatus(id, requester)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+		if errors.Is(err, Usecases.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"loan": loan})
 }
 
-// ViewAllLoans handles retrieving all loans
+// ViewLoanSchedule handles retrieving the installment schedule of an approved loan
+func (lc *LoanController) ViewLoanSchedule(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	schedule, err := lc.LoanUsecase.ViewLoanSchedule(id, requester)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schedule not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedule": schedule})
+}
+
+// ViewLoanHistory handles retrieving the status history of a loan
+func (lc *LoanController) ViewLoanHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	history, err := lc.LoanUsecase.ViewLoanHistory(id, requester)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// ViewMyLoans handles listing the loans of the authenticated borrower
+func (lc *LoanController) ViewMyLoans(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	filter := Domain.LoanFilter{
+		UserID: userID,
+		Status: c.Query("status"),
+		SortBy: c.Query("sort"),
+		Order:  c.Query("order"),
+		Page:   page,
+		Limit:  limit,
+	}
+
+	result, err := lc.LoanUsecase.ViewMyLoans(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ViewAllLoans handles listing loans across all borrowers with filters and cursor pagination
 func (lc *LoanController) ViewAllLoans(c *gin.Context) {
-	status := c.Query("status")
-	order := c.Query("order")
+	search, err := loanSearchFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	loans, err := lc.LoanUsecase.ViewAllLoans(status, order)
+	if value := c.Query("limit"); value != "" {
+		if search.Limit, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+
+	result, err := lc.LoanUsecase.ViewAllLoans(search)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"loans": loans})
+	c.JSON(http.StatusOK, result)
 }
 
 // ApproveRejectLoan handles loan approval or rejection
@@ -123,14 +238,26 @@ func (lc *LoanController) ApproveRejectLoan(c *gin.Context) {
 	}
 	input.Status = inp.Status
 	input.ChangedBy = changedBy
+	input.Roles = c.GetStringSlice("roles")
+	input.Comment = inp.Comment
+	input.InterestRate = inp.InterestRate
+	input.InterestMethod = inp.InterestMethod
 
-	err = lc.LoanUsecase.ApproveRejectLoan(id, input)
+	step, err := lc.LoanUsecase.ApproveRejectLoan(id, input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Loan status updated successfully"})
+	if step != nil && step.Action == Domain.ApprovalStepRecommended {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Loan recommended for approval; a different user must give the final approval", "step": step})
+		return
+	}
+	if step != nil && step.Action == Domain.ApprovalStepEscalated {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Loan escalated to an approver with a higher approval limit", "step": step})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Loan status updated successfully", "step": step})
 }
 
 // DeleteLoan handles loan deletion
@@ -146,6 +273,124 @@ func (lc *LoanController) DeleteLoan(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Loan deleted successfully"})
 }
 
+// UpdateLoan handles a borrower amending a pending loan application
+func (lc *LoanController) UpdateLoan(c *gin.Context) {
+	id := c.Param("id")
+
+	var input Domain.LoanUpdateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	loan, err := lc.LoanUsecase.UpdateLoan(id, input, requester)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"loan": loan})
+}
+
+// WithdrawLoan handles a borrower withdrawing a pending loan application
+func (lc *LoanController) WithdrawLoan(c *gin.Context) {
+	id := c.Param("id")
+
+	var inp struct {
+		Comment string `json:"comment"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&inp); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = lc.LoanUsecase.WithdrawLoan(id, inp.Comment, requester)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Loan withdrawn successfully"})
+}
+
+// SubmitLoan handles submitting a draft loan application for review
+func (lc *LoanController) SubmitLoan(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = lc.LoanUsecase.SubmitLoan(id, requester)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Loan submitted successfully"})
+}
+
+// ViewLoanRevisions handles retrieving the edits a borrower made to a loan application
+func (lc *LoanController) ViewLoanRevisions(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	revisions, err := lc.LoanUsecase.ViewLoanRevisions(id, requester)
+	if err != nil {
+		c.JSON(loanErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// AssignReviewers handles designating the staff members who may view a loan
+func (lc *LoanController) AssignReviewers(c *gin.Context) {
+	id := c.Param("id")
+
+	var input Domain.LoanReviewersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	changedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.ChangedBy = changedBy
+
+	err = lc.LoanUsecase.AssignReviewers(id, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Loan reviewers updated successfully"})
+}
+
 func (lc *LoanController) GetLogs(c *gin.Context) {
 	id := c.Param("id")
 
@@ -157,3 +402,94 @@ func (lc *LoanController) GetLogs(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Loan deleted successfully"})
 }
+
+// loanErrorStatus maps loan lifecycle errors to HTTP status codes
+func loanErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, Usecases.ErrLoanNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, Usecases.ErrTransitionNotPermitted), errors.Is(err, Usecases.ErrNotLoanOwner),
+		errors.Is(err, Usecases.ErrOwnLoan), errors.Is(err, Usecases.ErrApprovalAuthority), errors.Is(err, Usecases.ErrApprovalLimit):
+		return http.StatusForbidden
+	case errors.Is(err, Usecases.ErrInvalidTransition), errors.Is(err, Usecases.ErrLoanNotEditable),
+		errors.Is(err, Usecases.ErrSameApprover), errors.Is(err, Usecases.ErrAlreadyDisbursing):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// requesterFromContext identifies the authenticated user for loan access checks
+func requesterFromContext(c *gin.Context) (Domain.Requester, error) {
+	userID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		return Domain.Requester{}, err
+	}
+
+	return Domain.Requester{UserID: userID, Roles: c.GetStringSlice("roles")}, nil
+}
+
+// loanSearchFromQuery reads the admin loan filters shared by the listing and the export
+func loanSearchFromQuery(c *gin.Context) (Domain.LoanSearch, error) {
+	search := Domain.LoanSearch{
+		Status:   c.Query("status"),
+		Currency: c.Query("currency"),
+		Purpose:  c.Query("purpose"),
+		SortBy:   c.Query("sort"),
+		Order:    c.Query("order"),
+		Cursor:   c.Query("cursor"),
+	}
+
+	var err error
+	if value := c.Query("user_id"); value != "" {
+		if search.UserID, err = primitive.ObjectIDFromHex(value); err != nil {
+			return Domain.LoanSearch{}, errors.New("Invalid user_id")
+		}
+	}
+	if value := c.Query("term"); value != "" {
+		if search.Term, err = strconv.Atoi(value); err != nil {
+			return Domain.LoanSearch{}, errors.New("Invalid term")
+		}
+	}
+
+	amounts := map[string]**Domain.Money{"min_amount": &search.MinAmount, "max_amount": &search.MaxAmount}
+	for param, target := range amounts {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		amount, err := Domain.ParseMoney(value, "")
+		if err != nil {
+			return Domain.LoanSearch{}, errors.New("Invalid " + param)
+		}
+		*target = &amount
+	}
+
+	dates := map[string]**time.Time{
+		"created_from": &search.CreatedFrom,
+		"created_to":   &search.CreatedTo,
+		"updated_from": &search.UpdatedFrom,
+		"updated_to":   &search.UpdatedTo,
+	}
+	for param, target := range dates {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		date, err := parseDateParam(value)
+		if err != nil {
+			return Domain.LoanSearch{}, errors.New("Invalid " + param)
+		}
+		*target = &date
+	}
+
+	return search, nil
+}
+
+// parseDateParam accepts an RFC 3339 timestamp or a plain YYYY-MM-DD date
+func parseDateParam(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PaymentController struct {
+	PaymentUsecase Usecases.PaymentUsecase
+}
+
+// NewPaymentController creates a new instance of PaymentController
+func NewPaymentController(paymentUsecase Usecases.PaymentUsecase) *PaymentController {
+	return &PaymentController{
+		PaymentUsecase: paymentUsecase,
+	}
+}
+
+// RecordPayment handles recording a repayment against a loan
+func (pc *PaymentController) RecordPayment(c *gin.Context) {
+	id := c.Param("id")
+
+	var input Domain.PaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	recordedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.RecordedBy = recordedBy
+
+	payment, err := pc.PaymentUsecase.RecordPayment(id, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payment": payment})
+}
+
+// ViewPayments handles retrieving the payment history of a loan
+func (pc *PaymentController) ViewPayments(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	payments, err := pc.PaymentUsecase.ViewPayments(id, requester)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payments": payments})
+}
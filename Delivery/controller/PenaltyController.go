@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PenaltyController struct {
+	PenaltyUsecase Usecases.PenaltyUsecase
+}
+
+// NewPenaltyController creates a new instance of PenaltyController
+func NewPenaltyController(penaltyUsecase Usecases.PenaltyUsecase) *PenaltyController {
+	return &PenaltyController{
+		PenaltyUsecase: penaltyUsecase,
+	}
+}
+
+// GetPolicies handles listing the penalty policy of every currency
+func (pc *PenaltyController) GetPolicies(c *gin.Context) {
+	policies, err := pc.PenaltyUsecase.GetPolicies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+// SetPolicy handles creating or replacing the penalty policy of a currency
+func (pc *PenaltyController) SetPolicy(c *gin.Context) {
+	var input Domain.PenaltyPolicyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	policy, err := pc.PenaltyUsecase.SetPolicy(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policy": policy})
+}
+
+// DeletePolicy handles removing the penalty policy of a currency
+func (pc *PenaltyController) DeletePolicy(c *gin.Context) {
+	deletedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = pc.PenaltyUsecase.DeletePolicy(c.Param("currency"), deletedBy)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrPenaltyPolicyNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Penalty policy deleted successfully"})
+}
+
+// AssessPenalties handles running the late fee and penalty interest sweep immediately
+func (pc *PenaltyController) AssessPenalties(c *gin.Context) {
+	runBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	result, err := pc.PenaltyUsecase.AssessPenalties(runBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
+
+// ViewCharges handles retrieving the late fees and penalty interest posted to a loan
+func (pc *PenaltyController) ViewCharges(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	charges, err := pc.PenaltyUsecase.ViewCharges(id, requester)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"charges": charges})
+}
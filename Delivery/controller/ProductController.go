@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProductController struct {
+	ProductUsecase Usecases.LoanProductUsecase
+}
+
+// NewProductController creates a new instance of ProductController
+func NewProductController(productUsecase Usecases.LoanProductUsecase) *ProductController {
+	return &ProductController{
+		ProductUsecase: productUsecase,
+	}
+}
+
+// CreateProduct handles creating a loan product
+func (pc *ProductController) CreateProduct(c *gin.Context) {
+	var input Domain.LoanProductInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	createdBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.CreatedBy = createdBy
+
+	product, err := pc.ProductUsecase.CreateProduct(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"product": product})
+}
+
+// UpdateProduct handles publishing a new version of a loan product
+func (pc *ProductController) UpdateProduct(c *gin.Context) {
+	id := c.Param("id")
+
+	var input Domain.LoanProductInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	createdBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.CreatedBy = createdBy
+
+	product, err := pc.ProductUsecase.UpdateProduct(id, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"product": product})
+}
+
+// GetProduct handles retrieving the latest version of a loan product
+func (pc *ProductController) GetProduct(c *gin.Context) {
+	id := c.Param("id")
+
+	product, err := pc.ProductUsecase.GetProduct(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"product": product})
+}
+
+// GetAllProducts handles listing every loan product for admins
+func (pc *ProductController) GetAllProducts(c *gin.Context) {
+	products, err := pc.ProductUsecase.GetAllProducts(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": products})
+}
+
+// GetActiveProducts handles listing the loan products borrowers can apply for
+func (pc *ProductController) GetActiveProducts(c *gin.Context) {
+	products, err := pc.ProductUsecase.GetAllProducts(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"products": products})
+}
+
+// DeleteProduct handles withdrawing a loan product from new applications
+func (pc *ProductController) DeleteProduct(c *gin.Context) {
+	id := c.Param("id")
+
+	err := pc.ProductUsecase.DeleteProduct(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product deactivated successfully"})
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReminderController struct {
+	ReminderUsecase Usecases.ReminderUsecase
+}
+
+// NewReminderController creates a new instance of ReminderController
+func NewReminderController(reminderUsecase Usecases.ReminderUsecase) *ReminderController {
+	return &ReminderController{
+		ReminderUsecase: reminderUsecase,
+	}
+}
+
+// GetSettings handles retrieving the payment reminder settings
+func (rc *ReminderController) GetSettings(c *gin.Context) {
+	settings, err := rc.ReminderUsecase.GetSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateSettings handles replacing the payment reminder settings
+func (rc *ReminderController) UpdateSettings(c *gin.Context) {
+	var input Domain.ReminderSettings
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	settings, err := rc.ReminderUsecase.UpdateSettings(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// ViewDeliveries handles retrieving the reminder emails sent for a loan
+func (rc *ReminderController) ViewDeliveries(c *gin.Context) {
+	id := c.Param("id")
+
+	requester, err := requesterFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	deliveries, err := rc.ReminderUsecase.ViewDeliveries(id, requester)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrLoanNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Loan not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reminders": deliveries})
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"Loan_Tracker/infrastructure"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// exportErrorMarker is the last row of an export that failed after rows were streamed
+const exportErrorMarker = "ERROR: export incomplete, please run it again"
+
+type ReportController struct {
+	ReportUsecase Usecases.ReportUsecase
+}
+
+// NewReportController creates a new instance of ReportController
+func NewReportController(reportUsecase Usecases.ReportUsecase) *ReportController {
+	return &ReportController{
+		ReportUsecase: reportUsecase,
+	}
+}
+
+// Portfolio handles retrieving the loan book analytics
+func (rc *ReportController) Portfolio(c *gin.Context) {
+	query := Domain.PortfolioQuery{
+		ReportingCurrency: c.Query("reporting_currency"),
+		Interval:          c.Query("interval"),
+	}
+	dates := map[string]**time.Time{"from": &query.From, "to": &query.To}
+	for param, target := range dates {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		date, err := parseDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return
+		}
+		*target = &date
+	}
+
+	report, err := rc.ReportUsecase.Portfolio(query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// ExportLoans handles streaming the loan book as a CSV or XLSX file
+func (rc *ReportController) ExportLoans(c *gin.Context) {
+	search, err := loanSearchFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	search.Cursor = ""
+
+	var columns []string
+	if value := c.Query("columns"); value != "" {
+		for _, column := range strings.Split(value, ",") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+	}
+
+	requestedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	writer, ok := exportWriter(c, "loans")
+	if !ok {
+		return
+	}
+
+	err = rc.ReportUsecase.ExportLoans(search, columns, requestedBy, writer)
+	finishExport(c, writer, err)
+}
+
+// Delinquency handles retrieving the days-past-due aging and portfolio at risk of the active loan book
+func (rc *ReportController) Delinquency(c *gin.Context) {
+	if value := c.Query("date"); value != "" {
+		date, err := parseDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date"})
+			return
+		}
+
+		report, err := rc.ReportUsecase.DelinquencySnapshot(date)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No delinquency snapshot for this date"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"report": report})
+		return
+	}
+
+	report, err := rc.ReportUsecase.DelinquencyReport(c.Query("include_loans") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// ClassifyDelinquency handles running the daily delinquency classification
+func (rc *ReportController) ClassifyDelinquency(c *gin.Context) {
+	runBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	report, err := rc.ReportUsecase.ClassifyDelinquency(runBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// ExportDelinquency handles streaming the loan level delinquency classification as a file
+func (rc *ReportController) ExportDelinquency(c *gin.Context) {
+	writer, ok := exportWriter(c, "delinquency")
+	if !ok {
+		return
+	}
+
+	err := rc.ReportUsecase.ExportDelinquency(writer)
+	finishExport(c, writer, err)
+}
+
+// exportWriter picks the row writer for the requested format, csv by default, and sets the download headers
+func exportWriter(c *gin.Context, name string) (infrastructure.RowWriter, bool) {
+	format := c.DefaultQuery("format", "csv")
+
+	var writer infrastructure.RowWriter
+	var contentType string
+	switch format {
+	case "csv":
+		writer, contentType = infrastructure.NewCSVRowWriter(c.Writer), "text/csv; charset=utf-8"
+	case "xlsx":
+		writer, contentType = infrastructure.NewXLSXRowWriter(c.Writer), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+		return nil, false
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return writer, true
+}
+
+// finishExport closes a streamed export, or reports err if nothing has been streamed yet
+func finishExport(c *gin.Context, writer infrastructure.RowWriter, err error) {
+	if err == nil {
+		if err = writer.Close(); err == nil {
+			return
+		}
+		if c.Writer.Written() {
+			log.Printf("export aborted: %v", err)
+			return
+		}
+	}
+
+	if !c.Writer.Written() {
+		// Nothing has been streamed yet, so the client can still get a JSON error
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Rows have already gone out with a 200, so end the file with a marker row
+	// rather than leave a truncated file that looks complete
+	log.Printf("export aborted: %v", err)
+	if err := writer.WriteRow([]string{exportErrorMarker}); err != nil {
+		log.Printf("failed to mark export as incomplete: %v", err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("failed to mark export as incomplete: %v", err)
+	}
+}
+
+// GetFXRates handles listing the FX rate table
+func (rc *ReportController) GetFXRates(c *gin.Context) {
+	rates, err := rc.ReportUsecase.GetFXRates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
+
+// SetFXRate handles creating or replacing the rate of a currency pair
+func (rc *ReportController) SetFXRate(c *gin.Context) {
+	var input Domain.FXRateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	rate, err := rc.ReportUsecase.SetFXRate(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rate": rate})
+}
+
+// DeleteFXRate handles removing the rate of a currency pair
+func (rc *ReportController) DeleteFXRate(c *gin.Context) {
+	deletedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err = rc.ReportUsecase.DeleteFXRate(c.Param("from"), c.Param("to"), deletedBy)
+	if err != nil {
+		if errors.Is(err, Usecases.ErrFXRateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "FX rate deleted successfully"})
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"Loan_Tracker/Domain"
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReviewController struct {
+	ReviewUsecase Usecases.ReviewUsecase
+}
+
+// NewReviewController creates a new instance of ReviewController
+func NewReviewController(reviewUsecase Usecases.ReviewUsecase) *ReviewController {
+	return &ReviewController{
+		ReviewUsecase: reviewUsecase,
+	}
+}
+
+// GetSettings handles retrieving the review queue settings
+func (rc *ReviewController) GetSettings(c *gin.Context) {
+	settings, err := rc.ReviewUsecase.GetSettings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateSettings handles replacing the review queue settings
+func (rc *ReviewController) UpdateSettings(c *gin.Context) {
+	var input Domain.ReviewSettings
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	updatedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.UpdatedBy = updatedBy
+
+	settings, err := rc.ReviewUsecase.UpdateSettings(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// AssignLoan handles assigning or reassigning a loan to a loan officer
+func (rc *ReviewController) AssignLoan(c *gin.Context) {
+	var input Domain.LoanAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	assignedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	input.AssignedBy = assignedBy
+
+	assignment, err := rc.ReviewUsecase.AssignLoan(c.Param("id"), input)
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignment": assignment})
+}
+
+// ViewAssignments handles retrieving the assignment history of a loan
+func (rc *ReviewController) ViewAssignments(c *gin.Context) {
+	assignments, err := rc.ReviewUsecase.ViewAssignments(c.Param("id"))
+	if err != nil {
+		c.JSON(reviewErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assignments": assignments})
+}
+
+// ViewQueue handles listing every application waiting for review
+func (rc *ReviewController) ViewQueue(c *gin.Context) {
+	filter := Domain.ReviewQueueFilter{
+		Unassigned:  c.Query("unassigned") == "true",
+		OverdueOnly: c.Query("overdue") == "true",
+	}
+	if value := c.Query("officer_id"); value != "" {
+		officerID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid officer ID"})
+			return
+		}
+		filter.OfficerID = officerID
+	}
+
+	rc.respondWithQueue(c, filter)
+}
+
+// ViewMyQueue handles listing the applications assigned to the requesting officer
+func (rc *ReviewController) ViewMyQueue(c *gin.Context) {
+	officerID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	rc.respondWithQueue(c, Domain.ReviewQueueFilter{OfficerID: officerID, OverdueOnly: c.Query("overdue") == "true"})
+}
+
+func (rc *ReviewController) respondWithQueue(c *gin.Context, filter Domain.ReviewQueueFilter) {
+	items, err := rc.ReviewUsecase.ViewQueue(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"queue": items})
+}
+
+// reviewErrorStatus maps review queue errors to HTTP status codes
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, Usecases.ErrLoanNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, Usecases.ErrLoanNotInReview), errors.Is(err, Usecases.ErrAlreadyAssigned),
+		errors.Is(err, Usecases.ErrNoOfficers):
+		return http.StatusConflict
+	case errors.Is(err, Usecases.ErrInvalidOfficer), errors.Is(err, Usecases.ErrAssignmentTarget):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
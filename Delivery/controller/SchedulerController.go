@@ -0,0 +1,79 @@
+package controller
+
+import (
+	Usecases "Loan_Tracker/Usecase"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type SchedulerController struct {
+	SchedulerUsecase Usecases.SchedulerUsecase
+}
+
+// NewSchedulerController creates a new instance of SchedulerController
+func NewSchedulerController(schedulerUsecase Usecases.SchedulerUsecase) *SchedulerController {
+	return &SchedulerController{
+		SchedulerUsecase: schedulerUsecase,
+	}
+}
+
+// ListJobs handles listing the background jobs with their schedules and last runs
+func (sc *SchedulerController) ListJobs(c *gin.Context) {
+	jobs, err := sc.SchedulerUsecase.ListJobs()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// TriggerJob handles running a background job immediately
+func (sc *SchedulerController) TriggerJob(c *gin.Context) {
+	triggeredBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	run, err := sc.SchedulerUsecase.TriggerJob(c.Param("name"), triggeredBy)
+	if err != nil {
+		c.JSON(jobErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"run": run})
+}
+
+// ViewJobRuns handles retrieving the run history of a background job
+func (sc *SchedulerController) ViewJobRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+
+	runs, err := sc.SchedulerUsecase.ViewRuns(c.Param("name"), limit)
+	if err != nil {
+		c.JSON(jobErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// jobErrorStatus maps scheduler errors to HTTP status codes.
+func jobErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, Usecases.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, Usecases.ErrJobRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"Loan_Tracker/Domain"
 	Usecases "Loan_Tracker/Usecase"
 	"Loan_Tracker/infrastructure"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var jwtKey []byte
@@ -125,7 +127,7 @@ func (uc *UserController) RefreshToken(c *gin.Context) {
 	}
 	claims.Username = username
 
-	accessToken, err := infrastructure.GenerateJWT(claims.ID, claims.Username, claims.Role)
+	accessToken, err := infrastructure.GenerateJWT(claims.ID, claims.Username, claims.Roles)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -163,7 +165,7 @@ func (uc *UserController) FindUser(c *gin.Context) {
 		return
 	}
 
-	if (user == Domain.User{}) {
+	if user.ID.IsZero() {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
@@ -191,6 +193,23 @@ func (uc *UserController) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User logged out successfully"})
 }
 
+// UpdateEmailPreferences handles opting the user in or out of non-essential emails
+func (uc *UserController) UpdateEmailPreferences(c *gin.Context) {
+	var input Domain.EmailPreferencesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	err := uc.UserUsecase.UpdateEmailPreferences(c.GetString("username"), input)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email preferences updated successfully"})
+}
+
 func (uc *UserController) ChangePassword(c *gin.Context) {
 	var input Domain.ChangePasswordInput
 
@@ -243,11 +262,52 @@ func (uc *UserController) ForgotPassword(c *gin.Context) {
 
 // userController.go
 func (uc *UserController) GetAllUsers(c *gin.Context) {
-	users, err := uc.UserUsecase.GetAllUsers()
+	users, err := uc.UserUsecase.GetAllUsers(c.Query("role"))
 	if err != nil {
+		if errors.Is(err, Usecases.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"users": users})
 }
+
+// GetRoles handles listing the roles and the permissions they grant
+func (uc *UserController) GetRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"roles": uc.UserUsecase.GetRoles()})
+}
+
+// UpdateUserRoles handles replacing the roles assigned to a user
+func (uc *UserController) UpdateUserRoles(c *gin.Context) {
+	var input Domain.RoleAssignmentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	changedBy, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := uc.UserUsecase.UpdateRoles(c.Param("id"), input, changedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, Usecases.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, Usecases.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, Usecases.ErrLastAdmin), errors.Is(err, Usecases.ErrOwnAdminRole):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
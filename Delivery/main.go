@@ -1,16 +1,21 @@
 package main
 
 import (
+	"Loan_Tracker/Delivery/config"
 	"Loan_Tracker/Delivery/controller"
 	"Loan_Tracker/Delivery/router"
+	"Loan_Tracker/Domain"
 	repository "Loan_Tracker/Repository"
 	Usecases "Loan_Tracker/Usecase"
 	"Loan_Tracker/infrastructure"
 	"context"
+	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -37,26 +42,231 @@ func main() {
 	tokenCollection := database.Collection("Token")
 	loanCollection := database.Collection("Loan")
 	logCollection := database.Collection("Log")
+	scheduleCollection := database.Collection("Schedule")
+	paymentCollection := database.Collection("Payment")
+	loanStatusCollection := database.Collection("loan_status_history")
+	productCollection := database.Collection("LoanProduct")
+	fxRateCollection := database.Collection("FXRate")
+	disbursementCollection := database.Collection("Disbursement")
+	loanRevisionCollection := database.Collection("loan_revisions")
+	delinquencyCollection := database.Collection("delinquency_snapshots")
+	penaltyPolicyCollection := database.Collection("PenaltyPolicy")
+	loanChargeCollection := database.Collection("LoanCharge")
+	jobLeaseCollection := database.Collection("job_leases")
+	jobRunCollection := database.Collection("job_runs")
+	reminderSettingsCollection := database.Collection("ReminderSettings")
+	reminderDeliveryCollection := database.Collection("reminder_deliveries")
+	emailTemplateCollection := database.Collection("EmailTemplate")
+	outboxCollection := database.Collection("email_outbox")
+	approvalPolicyCollection := database.Collection("ApprovalPolicy")
+	approvalLimitCollection := database.Collection("ApprovalLimit")
+	loanApprovalCollection := database.Collection("loan_approvals")
+	reviewSettingsCollection := database.Collection("ReviewSettings")
+	loanAssignmentCollection := database.Collection("loan_assignments")
+	loanCommentCollection := database.Collection("loan_comments")
+
+	// Convert amounts stored before the Money type existed
+	currency := os.Getenv("DEFAULT_CURRENCY")
+	if currency == "" {
+		currency = Domain.DefaultCurrency
+	}
+	moneyFields := map[*mongo.Collection][]string{
+		loanCollection:     {"amount", "outstanding_principal", "outstanding_interest", "outstanding_fees"},
+		scheduleCollection: {"installments.principal", "installments.interest", "installments.total", "installments.remaining_balance", "installments.principal_paid", "installments.interest_paid"},
+		paymentCollection:  {"amount", "principal_paid", "interest_paid", "fees_paid", "allocations.principal", "allocations.interest"},
+		productCollection:  {"min_amount", "max_amount", "processing_fee"},
+	}
+	for collection, fields := range moneyFields {
+		migrated, err := repository.MigrateMoneyFields(collection, fields, currency)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if migrated > 0 {
+			log.Printf("Migrated %d %s documents to exact money amounts", migrated, collection.Name())
+		}
+	}
+	currencySources := map[*mongo.Collection]string{
+		loanCollection:    "amount.currency",
+		paymentCollection: "amount.currency",
+		productCollection: "min_amount.currency",
+	}
+	for collection, source := range currencySources {
+		if _, err := repository.BackfillCurrency(collection, source); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// Setup repositories
 	userRepository := repository.NewUserRepository(userCollection, tokenCollection)
 	loanRepository := repository.NewLoanRepository(loanCollection) // New loan repository
 	logRepository := repository.NewLogRepository(logCollection)
+	scheduleRepository := repository.NewScheduleRepository(scheduleCollection)
+	paymentRepository := repository.NewPaymentRepository(paymentCollection)
+	loanStatusRepository := repository.NewLoanStatusRepository(loanStatusCollection)
+	productRepository := repository.NewLoanProductRepository(productCollection)
+	fxRateRepository := repository.NewFXRateRepository(fxRateCollection)
+	disbursementRepository := repository.NewDisbursementRepository(disbursementCollection)
+	loanRevisionRepository := repository.NewLoanRevisionRepository(loanRevisionCollection)
+	delinquencyRepository := repository.NewDelinquencyRepository(delinquencyCollection)
+	penaltyPolicyRepository := repository.NewPenaltyPolicyRepository(penaltyPolicyCollection)
+	loanChargeRepository := repository.NewLoanChargeRepository(loanChargeCollection)
+	jobRepository := repository.NewJobRepository(jobLeaseCollection, jobRunCollection)
+	reminderRepository := repository.NewReminderRepository(reminderSettingsCollection, reminderDeliveryCollection)
+	emailTemplateRepository := repository.NewEmailTemplateRepository(emailTemplateCollection)
+	outboxRepository := repository.NewOutboxRepository(outboxCollection)
+	approvalRepository := repository.NewApprovalRepository(approvalPolicyCollection, approvalLimitCollection, loanApprovalCollection)
+	reviewRepository := repository.NewReviewRepository(reviewSettingsCollection, loanAssignmentCollection)
+	commentRepository := repository.NewCommentRepository(loanCommentCollection)
+	if err := loanRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := loanChargeRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := jobRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := reminderRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := outboxRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := disbursementRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := commentRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if err := productRepository.CreateIndexes(); err != nil {
+		log.Fatal(err)
+	}
+	if migrated, err := userRepository.MigrateRoles(); err != nil {
+		log.Fatal(err)
+	} else if migrated > 0 {
+		log.Printf("Migrated %d users to role-based access control", migrated)
+	}
+
 	// Setup services
-	emailService := infrastructure.NewEmailService()
+	smtpConfig := config.LoadSMTPConfig()
+	var mailer infrastructure.Mailer
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "", "smtp":
+		mailer = infrastructure.NewSMTPMailer(smtpConfig)
+	case "file":
+		dir := os.Getenv("MAIL_DROP_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		mailer = infrastructure.NewFileMailer(dir, smtpConfig.From)
+	case "log":
+		mailer = infrastructure.NewLogMailer()
+	default:
+		log.Fatalf("Invalid MAIL_TRANSPORT %q", transport)
+	}
+	// Payouts through a provider are disabled unless one is configured
+	var payoutProvider infrastructure.PayoutProvider
+	switch provider := os.Getenv("PAYOUT_PROVIDER"); provider {
+	case "":
+		log.Println("PAYOUT_PROVIDER is not set: disbursements can only be recorded manually")
+	case "fake":
+		log.Println("Using the fake payout provider: provider disbursements move no money")
+		payoutProvider = infrastructure.NewFakePayoutProvider()
+	default:
+		log.Fatalf("Invalid PAYOUT_PROVIDER %q", provider)
+	}
+
+	// Links in emails point to the public address of the service
+	baseURL := os.Getenv("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
 
 	// Setup use cases
-	userUsecase := Usecases.NewUserUsecase(userRepository, logRepository, emailService)
-	loanUsecase := Usecases.NewLoanUsecase(loanRepository, logRepository) // New loan use case
+	outboxUsecase := Usecases.NewOutboxUsecase(outboxRepository, logRepository, mailer)
+	emailTemplateUsecase := Usecases.NewEmailTemplateUsecase(emailTemplateRepository, logRepository, baseURL)
+	userUsecase := Usecases.NewUserUsecase(userRepository, logRepository, outboxUsecase, emailTemplateUsecase)
+	loanUsecase := Usecases.NewLoanUsecase(loanRepository, logRepository, scheduleRepository, loanStatusRepository, productRepository, loanRevisionRepository, approvalRepository, reviewRepository, userRepository) // New loan use case
 	logUsecase := Usecases.NewLogUsecase(logRepository)
+	productUsecase := Usecases.NewLoanProductUsecase(productRepository, logRepository)
+	reportUsecase := Usecases.NewReportUsecase(loanRepository, fxRateRepository, logRepository, userRepository, scheduleRepository, delinquencyRepository)
+	disbursementUsecase := Usecases.NewDisbursementUsecase(disbursementRepository, loanRepository, scheduleRepository, loanStatusRepository, productRepository, logRepository, payoutProvider)
+	paymentUsecase := Usecases.NewPaymentUsecase(paymentRepository, loanRepository, scheduleRepository, loanStatusRepository, productRepository, logRepository)
+	penaltyUsecase := Usecases.NewPenaltyUsecase(penaltyPolicyRepository, loanChargeRepository, loanRepository, scheduleRepository, logRepository)
+	reminderUsecase := Usecases.NewReminderUsecase(reminderRepository, loanRepository, scheduleRepository, userRepository, logRepository, outboxUsecase, emailTemplateUsecase)
+	schedulerUsecase := Usecases.NewSchedulerUsecase(jobRepository, logRepository)
+	approvalUsecase := Usecases.NewApprovalUsecase(approvalRepository, loanRepository, userRepository, logRepository)
+	reviewUsecase := Usecases.NewReviewUsecase(reviewRepository, loanRepository, userRepository, logRepository)
+	commentUsecase := Usecases.NewCommentUsecase(commentRepository, loanRepository, userRepository, logRepository, outboxUsecase, emailTemplateUsecase)
 
 	// Setup controllers
 	userController := controller.NewUserController(userUsecase)
 	loanController := controller.NewLoanController(loanUsecase) // New loan controller
 	logController := controller.NewLogController(logUsecase)
+	paymentController := controller.NewPaymentController(paymentUsecase)
+	productController := controller.NewProductController(productUsecase)
+	reportController := controller.NewReportController(reportUsecase)
+	disbursementController := controller.NewDisbursementController(disbursementUsecase)
+	penaltyController := controller.NewPenaltyController(penaltyUsecase)
+	schedulerController := controller.NewSchedulerController(schedulerUsecase)
+	reminderController := controller.NewReminderController(reminderUsecase)
+	emailTemplateController := controller.NewEmailTemplateController(emailTemplateUsecase, outboxUsecase)
+	approvalController := controller.NewApprovalController(approvalUsecase)
+	reviewController := controller.NewReviewController(reviewUsecase)
+	commentController := controller.NewCommentController(commentUsecase)
+
+	// Register background jobs
+	sweepInterval := "1h"
+	if value := os.Getenv("PENALTY_SWEEP_INTERVAL"); value != "" {
+		sweepInterval = value
+	}
+	jobs := []struct {
+		name        string
+		description string
+		schedule    string
+		run         Usecases.JobFunc
+	}{
+		{"purge-expired-tokens", "Delete expired and revoked access tokens", "0 * * * *", func() (string, error) {
+			deleted, err := userUsecase.PurgeExpiredTokens()
+			return fmt.Sprintf("deleted %d tokens", deleted), err
+		}},
+		{"resend-verification-emails", "Retry verification emails that failed at registration", "*/15 * * * *", func() (string, error) {
+			queued, failed, err := userUsecase.ResendVerificationEmails()
+			return fmt.Sprintf("queued %d emails, %d failed", queued, failed), err
+		}},
+		{"classify-delinquency", "Store days past due and the delinquency bucket of every repaying loan", "0 1 * * *", func() (string, error) {
+			report, err := reportUsecase.ClassifyDelinquency(primitive.NilObjectID)
+			return fmt.Sprintf("classified loans as of %s", report.AsOf.Format("2006-01-02")), err
+		}},
+		{"send-payment-reminders", "Email due date reminders and overdue notices to borrowers", "0 8 * * *", func() (string, error) {
+			result, err := reminderUsecase.SendReminders()
+			return fmt.Sprintf("queued %d reminders, %d suppressed, %d failed", result.Queued, result.Suppressed, result.Failed), err
+		}},
+		{"assess-penalties", "Post late fees and penalty interest", "@every " + sweepInterval, func() (string, error) {
+			result, err := penaltyUsecase.AssessPenalties(primitive.NilObjectID)
+			return fmt.Sprintf("posted %d charges to %d loans", result.ChargesPosted, result.LoansAssessed), err
+		}},
+		{"review-queue", "Assign waiting applications round-robin and flag those past the review SLA", "*/15 * * * *", func() (string, error) {
+			assigned, err := reviewUsecase.AssignWaiting()
+			if err != nil {
+				return fmt.Sprintf("assigned %d applications", assigned), err
+			}
+			flagged, err := reviewUsecase.FlagSLABreaches()
+			return fmt.Sprintf("assigned %d applications, flagged %d past the SLA", assigned, flagged), err
+		}},
+	}
+	for _, job := range jobs {
+		if err := schedulerUsecase.RegisterJob(job.name, job.description, job.schedule, job.run); err != nil {
+			log.Fatal(err)
+		}
+	}
+	schedulerUsecase.Start(context.Background())
+
+	// Deliver queued emails in the background
+	outboxUsecase.Start(context.Background(), 15*time.Second)
 
 	// Setup router
-	router := router.SetupRouter(userController, loanController, logController, tokenCollection)
+	router := router.SetupRouter(userController, loanController, logController, paymentController, productController, reportController, disbursementController, penaltyController, schedulerController, reminderController, emailTemplateController, approvalController, reviewController, commentController, tokenCollection)
 
 	// Start the server
 	log.Fatal(router.Run(":8080"))
@@ -2,13 +2,14 @@ package router
 
 import (
 	controller "Loan_Tracker/Delivery/controller"
+	"Loan_Tracker/Domain"
 	"Loan_Tracker/infrastructure"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SetupRouter(userController *controller.UserController, loanController *controller.LoanController, logController *controller.LogController, tokenCollection *mongo.Collection) *gin.Engine {
+func SetupRouter(userController *controller.UserController, loanController *controller.LoanController, logController *controller.LogController, paymentController *controller.PaymentController, productController *controller.ProductController, reportController *controller.ReportController, disbursementController *controller.DisbursementController, penaltyController *controller.PenaltyController, schedulerController *controller.SchedulerController, reminderController *controller.ReminderController, emailTemplateController *controller.EmailTemplateController, approvalController *controller.ApprovalController, reviewController *controller.ReviewController, commentController *controller.CommentController, tokenCollection *mongo.Collection) *gin.Engine {
 	router := gin.Default()
 
 	// Public routes (no authentication required)
@@ -23,20 +24,91 @@ func SetupRouter(userController *controller.UserController, loanController *cont
 	usersRoute.Use(infrastructure.AuthMiddleware(tokenCollection))
 	usersRoute.GET("/users/profile/:id", userController.FindUser)
 	usersRoute.PUT("/users/password-reset", userController.ChangePassword)
+	usersRoute.PUT("/users/email-preferences", userController.UpdateEmailPreferences)
 
 	// Loan routes (authentication required)
+	usersRoute.GET("/products", productController.GetActiveProducts)
 	usersRoute.POST("/loans", loanController.CreateLoan)
+	usersRoute.GET("/loans", loanController.ViewMyLoans)
 	usersRoute.GET("/loans/:id", loanController.ViewLoanStatus)
+	usersRoute.PATCH("/loans/:id", loanController.UpdateLoan)
+	usersRoute.POST("/loans/:id/submit", loanController.SubmitLoan)
+	usersRoute.POST("/loans/:id/withdraw", loanController.WithdrawLoan)
+	usersRoute.GET("/loans/:id/revisions", loanController.ViewLoanRevisions)
+	usersRoute.GET("/loans/:id/schedule", loanController.ViewLoanSchedule)
+	usersRoute.GET("/loans/:id/history", loanController.ViewLoanHistory)
+	usersRoute.GET("/loans/:id/payments", paymentController.ViewPayments)
+	usersRoute.GET("/loans/:id/charges", penaltyController.ViewCharges)
+	usersRoute.GET("/loans/:id/reminders", reminderController.ViewDeliveries)
+	usersRoute.GET("/loans/:id/comments", commentController.ViewComments)
+	usersRoute.POST("/loans/:id/comments", commentController.AddComment)
+	usersRoute.PATCH("/loans/:id/comments/:comment_id", commentController.EditComment)
 
-	adminRoute := usersRoute.Group("/")
-	adminRoute.Use(infrastructure.AdminMiddleware()) // Apply admin role middleware
+	// Staff routes (each requires a permission granted by one of the user's roles)
+	can := infrastructure.RequirePermission
 
-	adminRoute.GET("/admin/loans", loanController.ViewAllLoans)
-	adminRoute.PATCH("/admin/loans/:id/status", loanController.ApproveRejectLoan)
-	adminRoute.DELETE("/admin/loans/:id", loanController.DeleteLoan)
+	usersRoute.GET("/admin/loans", can(Domain.PermissionLoansRead), loanController.ViewAllLoans)
+	usersRoute.GET("/admin/loans/export", can(Domain.PermissionReportsRead), reportController.ExportLoans)
+	usersRoute.PATCH("/admin/loans/:id/status", can(Domain.PermissionLoansApprove), loanController.ApproveRejectLoan)
+	usersRoute.GET("/admin/loans/:id/approvals", can(Domain.PermissionLoansRead), approvalController.ViewApprovals)
+	usersRoute.GET("/admin/loans/escalated", can(Domain.PermissionLoansApprove), approvalController.ViewEscalations)
+	usersRoute.DELETE("/admin/loans/:id", can(Domain.PermissionLoansDelete), loanController.DeleteLoan)
+	usersRoute.PUT("/admin/loans/:id/reviewers", can(Domain.PermissionLoansAssign), loanController.AssignReviewers)
+	usersRoute.PUT("/admin/loans/:id/assignment", can(Domain.PermissionLoansAssign), reviewController.AssignLoan)
+	usersRoute.GET("/admin/loans/:id/assignments", can(Domain.PermissionLoansRead), reviewController.ViewAssignments)
+	usersRoute.GET("/admin/review-queue", can(Domain.PermissionLoansAssign), reviewController.ViewQueue)
+	usersRoute.GET("/officer/queue", can(Domain.PermissionLoansApprove), reviewController.ViewMyQueue)
+	usersRoute.POST("/admin/loans/:id/disbursement", can(Domain.PermissionLoansDisburse), disbursementController.DisburseLoan)
+	usersRoute.GET("/admin/loans/:id/disbursement", can(Domain.PermissionLoansRead), disbursementController.ViewDisbursement)
+	usersRoute.POST("/loans/:id/payments", can(Domain.PermissionPaymentsRecord), paymentController.RecordPayment)
 
-	adminRoute.GET("/admin/users", userController.GetAllUsers)
-	adminRoute.DELETE("/admin/users/:id", userController.DeleteUser)
-	adminRoute.GET("/admin/logs", logController.GetLogs)
+	usersRoute.GET("/admin/products", can(Domain.PermissionProductsManage), productController.GetAllProducts)
+	usersRoute.POST("/admin/products", can(Domain.PermissionProductsManage), productController.CreateProduct)
+	usersRoute.GET("/admin/products/:id", can(Domain.PermissionProductsManage), productController.GetProduct)
+	usersRoute.PUT("/admin/products/:id", can(Domain.PermissionProductsManage), productController.UpdateProduct)
+	usersRoute.DELETE("/admin/products/:id", can(Domain.PermissionProductsManage), productController.DeleteProduct)
+
+	usersRoute.GET("/admin/reports/portfolio", can(Domain.PermissionReportsRead), reportController.Portfolio)
+	usersRoute.GET("/admin/reports/delinquency", can(Domain.PermissionReportsRead), reportController.Delinquency)
+	usersRoute.POST("/admin/reports/delinquency/classify", can(Domain.PermissionJobsRun), reportController.ClassifyDelinquency)
+	usersRoute.GET("/admin/reports/delinquency/export", can(Domain.PermissionReportsRead), reportController.ExportDelinquency)
+	usersRoute.GET("/admin/fx-rates", can(Domain.PermissionReportsRead), reportController.GetFXRates)
+	usersRoute.PUT("/admin/fx-rates", can(Domain.PermissionSettingsManage), reportController.SetFXRate)
+	usersRoute.DELETE("/admin/fx-rates/:from/:to", can(Domain.PermissionSettingsManage), reportController.DeleteFXRate)
+
+	usersRoute.GET("/admin/penalty-policies", can(Domain.PermissionSettingsManage), penaltyController.GetPolicies)
+	usersRoute.PUT("/admin/penalty-policies", can(Domain.PermissionSettingsManage), penaltyController.SetPolicy)
+	usersRoute.DELETE("/admin/penalty-policies/:currency", can(Domain.PermissionSettingsManage), penaltyController.DeletePolicy)
+	usersRoute.POST("/admin/penalties/sweep", can(Domain.PermissionJobsRun), penaltyController.AssessPenalties)
+
+	usersRoute.GET("/admin/approval-policies", can(Domain.PermissionSettingsManage), approvalController.GetPolicies)
+	usersRoute.PUT("/admin/approval-policies", can(Domain.PermissionSettingsManage), approvalController.SetPolicy)
+	usersRoute.DELETE("/admin/approval-policies/:currency", can(Domain.PermissionSettingsManage), approvalController.DeletePolicy)
+	usersRoute.GET("/admin/approval-limits", can(Domain.PermissionSettingsManage), approvalController.GetLimits)
+	usersRoute.PUT("/admin/approval-limits", can(Domain.PermissionSettingsManage), approvalController.SetLimit)
+	usersRoute.DELETE("/admin/approval-limits/:id", can(Domain.PermissionSettingsManage), approvalController.DeleteLimit)
+	usersRoute.GET("/admin/review-settings", can(Domain.PermissionSettingsManage), reviewController.GetSettings)
+	usersRoute.PUT("/admin/review-settings", can(Domain.PermissionSettingsManage), reviewController.UpdateSettings)
+
+	usersRoute.GET("/admin/reminder-settings", can(Domain.PermissionSettingsManage), reminderController.GetSettings)
+	usersRoute.PUT("/admin/reminder-settings", can(Domain.PermissionSettingsManage), reminderController.UpdateSettings)
+
+	usersRoute.GET("/admin/email-templates", can(Domain.PermissionSettingsManage), emailTemplateController.GetTemplates)
+	usersRoute.PUT("/admin/email-templates", can(Domain.PermissionSettingsManage), emailTemplateController.SetTemplate)
+	usersRoute.DELETE("/admin/email-templates/:name/:locale", can(Domain.PermissionSettingsManage), emailTemplateController.DeleteTemplate)
+	usersRoute.POST("/admin/email-templates/:name/:locale/preview", can(Domain.PermissionSettingsManage), emailTemplateController.PreviewTemplate)
+	usersRoute.GET("/admin/emails", can(Domain.PermissionEmailsManage), emailTemplateController.GetOutbox)
+	usersRoute.GET("/admin/emails/:id", can(Domain.PermissionEmailsManage), emailTemplateController.GetOutboxMessage)
+	usersRoute.POST("/admin/emails/:id/retry", can(Domain.PermissionEmailsManage), emailTemplateController.RetryOutboxMessage)
+
+	usersRoute.GET("/admin/jobs", can(Domain.PermissionJobsRead), schedulerController.ListJobs)
+	usersRoute.POST("/admin/jobs/:name/run", can(Domain.PermissionJobsRun), schedulerController.TriggerJob)
+	usersRoute.GET("/admin/jobs/:name/runs", can(Domain.PermissionJobsRead), schedulerController.ViewJobRuns)
+
+	usersRoute.GET("/admin/users", can(Domain.PermissionUsersRead), userController.GetAllUsers)
+	usersRoute.GET("/admin/roles", can(Domain.PermissionUsersRead), userController.GetRoles)
+	usersRoute.PUT("/admin/users/:id/roles", can(Domain.PermissionUsersManage), userController.UpdateUserRoles)
+	usersRoute.DELETE("/admin/users/:id", can(Domain.PermissionUsersManage), userController.DeleteUser)
+	usersRoute.GET("/admin/logs", can(Domain.PermissionLogsRead), logController.GetLogs)
 	return router
 }
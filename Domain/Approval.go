@@ -0,0 +1,64 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Steps recorded while deciding on a loan application.
+const (
+	ApprovalStepRecommended = "recommended" // First approval of a loan under dual control
+	ApprovalStepEscalated   = "escalated"   // Approval by a user whose limit is below the loan amount
+	ApprovalStepApproved    = "approved"
+	ApprovalStepRejected    = "rejected"
+)
+
+// ApprovalPolicy puts loans in one currency above an amount under dual control:
+// one user recommends the loan and a different user with the
+// loans:final_approve permission approves it.
+type ApprovalPolicy struct {
+	ID               primitive.ObjectID `json:"id" bson:"id"`
+	Currency         string             `json:"currency" bson:"currency"`
+	DualControlAbove Money              `json:"dual_control_above" bson:"dual_control_above"` // Loans for more than this need two approvers
+	UpdatedBy        primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+	UpdatedAt        time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type ApprovalPolicyInput struct {
+	Currency         string             `json:"currency" bson:"currency"`
+	DualControlAbove Money              `json:"dual_control_above" bson:"dual_control_above"`
+	UpdatedBy        primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+}
+
+// ApprovalLimit caps the loan amount a role, or one user, may approve in a
+// currency. A user's own limit takes precedence over those of their roles;
+// with several roles the highest limit applies, and users without any limit
+// in a currency may approve any amount.
+type ApprovalLimit struct {
+	ID        primitive.ObjectID `json:"id" bson:"id"`
+	Role      string             `json:"role,omitempty" bson:"role,omitempty"`       // Set for role limits
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"` // Set for the limit of one user
+	Currency  string             `json:"currency" bson:"currency"`
+	MaxAmount Money              `json:"max_amount" bson:"max_amount"`
+	UpdatedBy primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type ApprovalLimitInput struct {
+	Role      string             `json:"role" bson:"role"`
+	UserID    string             `json:"user_id" bson:"user_id"`
+	Currency  string             `json:"currency" bson:"currency"`
+	MaxAmount Money              `json:"max_amount" bson:"max_amount"`
+	UpdatedBy primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+}
+
+// LoanApprovalStep records one decision taken on a loan application.
+type LoanApprovalStep struct {
+	ID        primitive.ObjectID `json:"id" bson:"id"`
+	LoanID    primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	Action    string             `json:"action" bson:"action"` // "recommended", "escalated", "approved", "rejected"
+	ActorID   primitive.ObjectID `json:"actor_id" bson:"actor_id"`
+	Comment   string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,40 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Who can read a loan comment.
+const (
+	CommentVisibilityInternal = "internal" // Staff only
+	CommentVisibilityBorrower = "borrower" // Staff and the borrower
+)
+
+var CommentVisibilities = []string{CommentVisibilityInternal, CommentVisibilityBorrower}
+
+// LoanComment is a note or message in the comment thread of a loan.
+type LoanComment struct {
+	ID         primitive.ObjectID `json:"id" bson:"id"`
+	LoanID     primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	AuthorID   primitive.ObjectID `json:"author_id" bson:"author_id"`
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	FromStaff  bool               `json:"from_staff" bson:"from_staff"` // False for messages written by the borrower
+	Visibility string             `json:"visibility" bson:"visibility"` // "internal", "borrower"
+	Body       string             `json:"body" bson:"body"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	EditedAt   *time.Time         `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	Edits      []LoanCommentEdit  `json:"edits,omitempty" bson:"edits,omitempty"` // Earlier versions of the body, oldest first
+}
+
+// LoanCommentEdit keeps the body a comment had before an edit.
+type LoanCommentEdit struct {
+	Body     string    `json:"body" bson:"body"`
+	EditedAt time.Time `json:"edited_at" bson:"edited_at"` // When this body was replaced
+}
+
+type LoanCommentInput struct {
+	Body       string `json:"body" bson:"body"`
+	Visibility string `json:"visibility" bson:"visibility"` // Defaults to "internal" for staff; borrowers can only write "borrower"
+}
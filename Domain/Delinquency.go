@@ -0,0 +1,63 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Days-past-due buckets of the delinquency aging report.
+const (
+	DelinquencyCurrent = "current"
+	Delinquency1To30   = "1-30"
+	Delinquency31To60  = "31-60"
+	Delinquency61To90  = "61-90"
+	DelinquencyOver90  = "90+"
+)
+
+var DelinquencyBuckets = []string{DelinquencyCurrent, Delinquency1To30, Delinquency31To60, Delinquency61To90, DelinquencyOver90}
+
+// LoanDelinquency classifies one loan by how long its oldest unpaid installment has been overdue.
+type LoanDelinquency struct {
+	LoanID               primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	UserID               primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Status               string             `json:"status" bson:"status"`
+	Currency             string             `json:"currency" bson:"currency"`
+	DaysPastDue          int                `json:"days_past_due" bson:"days_past_due"`
+	Bucket               string             `json:"bucket" bson:"bucket"`                                       // One of DelinquencyBuckets
+	OldestDueDate        *time.Time         `json:"oldest_due_date,omitempty" bson:"oldest_due_date,omitempty"` // Due date of the oldest overdue installment
+	OverdueAmount        Money              `json:"overdue_amount" bson:"overdue_amount"`                       // Unpaid amount of the installments already due
+	OutstandingPrincipal Money              `json:"outstanding_principal" bson:"outstanding_principal"`
+}
+
+// DelinquencyBucketTotal sums the loans of one bucket and currency.
+type DelinquencyBucketTotal struct {
+	Bucket               string `json:"bucket" bson:"bucket"`
+	Currency             string `json:"currency" bson:"currency"`
+	LoanCount            int    `json:"loan_count" bson:"loan_count"`
+	OutstandingPrincipal Money  `json:"outstanding_principal" bson:"outstanding_principal"`
+	OverdueAmount        Money  `json:"overdue_amount" bson:"overdue_amount"`
+}
+
+// PortfolioAtRisk is the share of outstanding principal held by loans more than
+// 30 and 90 days past due, for one currency.
+type PortfolioAtRisk struct {
+	Currency             string  `json:"currency" bson:"currency"`
+	OutstandingPrincipal Money   `json:"outstanding_principal" bson:"outstanding_principal"`
+	AtRisk30             Money   `json:"at_risk_30" bson:"at_risk_30"`
+	AtRisk90             Money   `json:"at_risk_90" bson:"at_risk_90"`
+	PAR30                float64 `json:"par30" bson:"par30"` // From 0 to 1
+	PAR90                float64 `json:"par90" bson:"par90"` // From 0 to 1
+}
+
+// DelinquencyReport is the aging of the active loan book on one day. Daily
+// classifications are stored so the risk figures can be followed over time;
+// stored snapshots do not keep the loan level rows.
+type DelinquencyReport struct {
+	ID          primitive.ObjectID       `json:"id" bson:"id"`
+	AsOf        time.Time                `json:"as_of" bson:"as_of"` // Start of the day, UTC
+	Buckets     []DelinquencyBucketTotal `json:"buckets" bson:"buckets"`
+	PAR         []PortfolioAtRisk        `json:"par" bson:"par"`
+	Loans       []LoanDelinquency        `json:"loans,omitempty" bson:"-"`
+	GeneratedAt time.Time                `json:"generated_at" bson:"generated_at"`
+}
@@ -0,0 +1,54 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var DisbursementMethods = []string{"bank_transfer", "mobile_money", "cash", "cheque"}
+
+// Disbursement statuses. A loan has a single disbursement record, created as
+// pending before any money moves. It becomes paid once the funds are out and
+// completed once the loan is active; a retry resumes a paid disbursement and
+// replaces a failed one, or a pending one whose request stopped part way.
+const (
+	DisbursementStatusPending   = "pending"
+	DisbursementStatusPaid      = "paid"
+	DisbursementStatusCompleted = "completed"
+	DisbursementStatusFailed    = "failed"
+)
+
+type BankAccount struct {
+	AccountName   string `json:"account_name" bson:"account_name"`
+	AccountNumber string `json:"account_number" bson:"account_number"`
+	BankName      string `json:"bank_name" bson:"bank_name"`
+	BranchCode    string `json:"branch_code,omitempty" bson:"branch_code,omitempty"`
+}
+
+// Disbursement records the transfer of an approved loan's funds to the borrower.
+type Disbursement struct {
+	ID          primitive.ObjectID `json:"id" bson:"id"`
+	LoanID      primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	Amount      Money              `json:"amount" bson:"amount"`
+	Currency    string             `json:"currency" bson:"currency"`
+	Method      string             `json:"method" bson:"method"`                         // "bank_transfer", "mobile_money", "cash", "cheque"
+	Provider    string             `json:"provider,omitempty" bson:"provider,omitempty"` // Payout provider that made the transfer, empty when recorded manually
+	Status      string             `json:"status" bson:"status"`                         // "pending", "paid", "completed", "failed"
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`       // Why the payout failed
+	Reference   string             `json:"reference" bson:"reference"`
+	BankAccount BankAccount        `json:"bank_account" bson:"bank_account"`
+	DisbursedAt time.Time          `json:"disbursed_at" bson:"disbursed_at"`
+	RecordedBy  primitive.ObjectID `json:"recorded_by" bson:"recorded_by"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type DisbursementInput struct {
+	Method      string             `json:"method" bson:"method"`
+	Reference   string             `json:"reference" bson:"reference"`       // Required for manually recorded transfers
+	BankAccount BankAccount        `json:"bank_account" bson:"bank_account"` // Required for bank transfers
+	DisbursedAt time.Time          `json:"disbursed_at" bson:"disbursed_at"` // Defaults to now
+	UseProvider bool               `json:"use_provider" bson:"use_provider"` // Send the funds through the payout provider instead of recording a manual transfer
+	RecordedBy  primitive.ObjectID `json:"recorded_by" bson:"recorded_by"`
+}
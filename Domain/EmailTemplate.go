@@ -0,0 +1,60 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Names of the emails the service sends.
+const (
+	EmailTemplateVerifyEmail     = "verify_email"
+	EmailTemplatePasswordReset   = "password_reset"
+	EmailTemplatePaymentUpcoming = "payment_upcoming"
+	EmailTemplatePaymentDue      = "payment_due"
+	EmailTemplatePaymentOverdue  = "payment_overdue"
+	EmailTemplateLoanMessage     = "loan_message"
+)
+
+var EmailTemplateNames = []string{
+	EmailTemplateVerifyEmail, EmailTemplatePasswordReset,
+	EmailTemplatePaymentUpcoming, EmailTemplatePaymentDue, EmailTemplatePaymentOverdue,
+	EmailTemplateLoanMessage,
+}
+
+// DefaultLocale is used for users without a locale and for emails that have no
+// variant in the user's locale.
+const DefaultLocale = "en"
+
+// EmailTemplate is one locale's variant of an email. Subject and Text are
+// text/template sources, HTML is an html/template source. Built-in templates
+// ship with the service; admins can override them or add locales.
+type EmailTemplate struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"id"`
+	Name      string             `json:"name" bson:"name"`     // One of EmailTemplateNames
+	Locale    string             `json:"locale" bson:"locale"` // e.g. "en", "fr", "pt-br"
+	Subject   string             `json:"subject" bson:"subject"`
+	Text      string             `json:"text" bson:"text"`
+	HTML      string             `json:"html,omitempty" bson:"html,omitempty"` // Optional; the email is sent as plain text only without it
+	BuiltIn   bool               `json:"built_in" bson:"-"`                    // Set on templates that ship with the service
+	UpdatedBy primitive.ObjectID `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+type EmailTemplateInput struct {
+	Name      string             `json:"name" bson:"name"`
+	Locale    string             `json:"locale" bson:"locale"`
+	Subject   string             `json:"subject" bson:"subject"`
+	Text      string             `json:"text" bson:"text"`
+	HTML      string             `json:"html" bson:"html"`
+	UpdatedBy primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+}
+
+// EmailMessage is a rendered email. It is sent as multipart/alternative when
+// it has both a text and an HTML body.
+type EmailMessage struct {
+	To      string `json:"to,omitempty" bson:"to"`
+	Subject string `json:"subject" bson:"subject"`
+	Text    string `json:"text" bson:"text"`
+	HTML    string `json:"html,omitempty" bson:"html,omitempty"`
+}
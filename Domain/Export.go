@@ -0,0 +1,17 @@
+package Domain
+
+// LoanExportColumns lists the columns that can be selected for a loan book export, in their default order.
+var LoanExportColumns = []string{
+	"loan_id", "user_id", "borrower_name", "borrower_email", "borrower_username",
+	"status", "currency", "amount", "term", "purpose",
+	"product_id", "product_version", "interest_rate", "interest_method",
+	"outstanding_principal", "outstanding_interest", "outstanding_fees", "outstanding_total",
+	"created_at", "updated_at", "disbursed_at", "first_due_date", "maturity_date",
+}
+
+// DefaultLoanExportColumns are exported when no columns are requested.
+var DefaultLoanExportColumns = []string{
+	"loan_id", "borrower_name", "borrower_email", "status", "currency", "amount", "term",
+	"interest_rate", "outstanding_principal", "outstanding_interest", "outstanding_fees", "outstanding_total",
+	"created_at", "disbursed_at", "maturity_date",
+}
@@ -0,0 +1,56 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Job run statuses.
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// How a job run was started.
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// JobLease is held by the replica currently running a job so that the other
+// replicas skip it. A lease that is not renewed before ExpiresAt may be taken
+// over. LastSlot is the latest scheduled run time that was claimed, so each
+// slot runs once even when the run ends before another replica's timer fires.
+type JobLease struct {
+	Job        string    `json:"job" bson:"job"`
+	Owner      string    `json:"owner" bson:"owner"` // Replica holding the lease, see JobRun.Owner
+	AcquiredAt time.Time `json:"acquired_at" bson:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	LastSlot   time.Time `json:"last_slot,omitempty" bson:"last_slot,omitempty"`
+}
+
+// JobRun is one execution of a background job.
+type JobRun struct {
+	ID          primitive.ObjectID `json:"id" bson:"id"`
+	Job         string             `json:"job" bson:"job"`
+	Trigger     string             `json:"trigger" bson:"trigger"` // "schedule", "manual"
+	TriggeredBy primitive.ObjectID `json:"triggered_by,omitempty" bson:"triggered_by,omitempty"`
+	Owner       string             `json:"owner" bson:"owner"`   // Host and process that ran the job
+	Status      string             `json:"status" bson:"status"` // "running", "succeeded", "failed"
+	Result      string             `json:"result,omitempty" bson:"result,omitempty"`
+	Error       string             `json:"error,omitempty" bson:"error,omitempty"`
+	StartedAt   time.Time          `json:"started_at" bson:"started_at"`
+	FinishedAt  *time.Time         `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// JobInfo describes a registered job for the admin listing.
+type JobInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Schedule    string     `json:"schedule"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	Lease       *JobLease  `json:"lease,omitempty"` // Present while a replica is running the job
+	LastRun     *JobRun    `json:"last_run,omitempty"`
+}
@@ -6,40 +6,196 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Loan lifecycle statuses; the allowed transitions between them live in the usecase layer.
+const (
+	LoanStatusDraft       = "draft"
+	LoanStatusSubmitted   = "submitted"
+	LoanStatusUnderReview = "under_review"
+	LoanStatusApproved    = "approved"
+	LoanStatusRejected    = "rejected"
+	LoanStatusDisbursed   = "disbursed"
+	LoanStatusActive      = "active"
+	LoanStatusPaidOff     = "paid_off"
+	LoanStatusDefaulted   = "defaulted"
+	LoanStatusWrittenOff  = "written_off"
+	LoanStatusWithdrawn   = "withdrawn"
+	LoanStatusCancelled   = "cancelled"
+
+	// LoanStatusPending is stored on loans created before the lifecycle existed and is treated as submitted.
+	LoanStatusPending = "pending"
+)
+
+var LoanStatuses = []string{
+	LoanStatusDraft, LoanStatusSubmitted, LoanStatusUnderReview, LoanStatusApproved, LoanStatusRejected,
+	LoanStatusDisbursed, LoanStatusActive, LoanStatusPaidOff, LoanStatusDefaulted, LoanStatusWrittenOff,
+	LoanStatusWithdrawn, LoanStatusCancelled, LoanStatusPending,
+}
+
+// Actors that may trigger a loan status transition.
+const (
+	LoanActorBorrower = "borrower"
+	LoanActorAdmin    = "admin"
+	LoanActorSystem   = "system"
+)
+
 type Loan struct {
 	ID        primitive.ObjectID `json:"id" bson:"id"`
 	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
-	Amount    float64            `json:"amount" bson:"amount"`
-	Term      int                `json:"term" bson:"term"` // In months
+	Amount    Money              `json:"amount" bson:"amount"`
+	Currency  string             `json:"currency" bson:"currency"` // Currency of every amount on the loan
+	Term      int                `json:"term" bson:"term"`         // In months
 	Purpose   string             `json:"purpose" bson:"purpose"`
-	Status    string             `json:"status" bson:"status"` // "pending", "approved", "rejected"
+	Status    string             `json:"status" bson:"status"` // One of LoanStatuses
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+
+	ProductID      primitive.ObjectID `json:"product_id,omitempty" bson:"product_id,omitempty"`
+	ProductVersion int                `json:"product_version,omitempty" bson:"product_version,omitempty"` // Product terms the loan was originated under
+	InterestRate   float64            `json:"interest_rate,omitempty" bson:"interest_rate,omitempty"`     // Annual nominal rate in percent
+	InterestMethod string             `json:"interest_method,omitempty" bson:"interest_method,omitempty"` // "reducing_balance", "flat"
+
+	OutstandingPrincipal Money `json:"outstanding_principal" bson:"outstanding_principal"`
+	OutstandingInterest  Money `json:"outstanding_interest" bson:"outstanding_interest"` // Scheduled interest not yet paid
+	OutstandingFees      Money `json:"outstanding_fees" bson:"outstanding_fees"`
+
+	Version     int                  `json:"version" bson:"version"`                               // Incremented on every borrower edit, see LoanRevision
+	ReviewerIDs []primitive.ObjectID `json:"reviewer_ids,omitempty" bson:"reviewer_ids,omitempty"` // Staff designated to review the loan
+	Escalation  *LoanEscalation      `json:"escalation,omitempty" bson:"escalation,omitempty"`     // Set when an approver's limit was below the amount
+
+	Assignment          *LoanAssignment `json:"assignment,omitempty" bson:"assignment,omitempty"`                         // Officer reviewing the application
+	ReviewSLABreachedAt *time.Time      `json:"review_sla_breached_at,omitempty" bson:"review_sla_breached_at,omitempty"` // Set once the application waited longer than the review SLA
+
+	DaysPastDue       int        `json:"days_past_due" bson:"days_past_due"`                               // Set by the daily delinquency classification
+	DelinquencyBucket string     `json:"delinquency_bucket,omitempty" bson:"delinquency_bucket,omitempty"` // One of DelinquencyBuckets
+	ClassifiedAt      *time.Time `json:"classified_at,omitempty" bson:"classified_at,omitempty"`
+
+	AppliedCharges []string `json:"-" bson:"applied_charges,omitempty"` // Keys of the LoanCharges added to the outstanding fees
+
+	DisbursedAt  *time.Time `json:"disbursed_at,omitempty" bson:"disbursed_at,omitempty"`
+	FirstDueDate *time.Time `json:"first_due_date,omitempty" bson:"first_due_date,omitempty"`
+	MaturityDate *time.Time `json:"maturity_date,omitempty" bson:"maturity_date,omitempty"` // Due date of the last installment
 }
 
+// LoanEscalation records that a loan was approved by a user whose approval
+// limit is below its amount, so that someone with higher authority decides.
+type LoanEscalation struct {
+	EscalatedBy   primitive.ObjectID `json:"escalated_by" bson:"escalated_by"`
+	EscalatedAt   time.Time          `json:"escalated_at" bson:"escalated_at"`
+	ApproverLimit Money              `json:"approver_limit" bson:"approver_limit"` // Limit of the user who escalated it
+	Comment       string             `json:"comment,omitempty" bson:"comment,omitempty"`
+}
+
+// OutstandingTotal is everything the borrower still owes on the loan.
+func (l Loan) OutstandingTotal() (Money, error) {
+	total, err := l.OutstandingPrincipal.Add(l.OutstandingInterest)
+	if err != nil {
+		return Money{}, err
+	}
+	return total.Add(l.OutstandingFees)
+}
+
+// LoanStatus is a single entry in a loan's status history.
 type LoanStatus struct {
+	ID             primitive.ObjectID `json:"id" bson:"id"`
+	LoanID         primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	PreviousStatus string             `json:"previous_status" bson:"previous_status"`
+	Status         string             `json:"status" bson:"status"` // One of LoanStatuses
+	ChangedAt      time.Time          `json:"changed_at" bson:"changed_at"`
+	ChangedBy      primitive.ObjectID `json:"changed_by" bson:"changed_by"` // UserID of the user who changed the status
+	Actor          string             `json:"actor" bson:"actor"`           // "borrower", "admin", "system"
+	Comment        string             `json:"comment,omitempty" bson:"comment,omitempty"`
+}
+
+// LoanUpdateInput amends a pending loan application; nil fields are left unchanged.
+type LoanUpdateInput struct {
+	Amount  *Money  `json:"amount" bson:"amount"`
+	Term    *int    `json:"term" bson:"term"` // In months
+	Purpose *string `json:"purpose" bson:"purpose"`
+}
+
+// LoanRevision records the fields a borrower changed in one edit of a pending loan.
+type LoanRevision struct {
 	ID        primitive.ObjectID `json:"id" bson:"id"`
 	LoanID    primitive.ObjectID `json:"loan_id" bson:"loan_id"`
-	Status    string             `json:"status" bson:"status"` // "pending", "approved", "rejected"
+	Version   int                `json:"version" bson:"version"` // Loan version the edit produced; the submitted application is version 0
+	Changes   []LoanFieldChange  `json:"changes" bson:"changes"`
+	ChangedBy primitive.ObjectID `json:"changed_by" bson:"changed_by"`
 	ChangedAt time.Time          `json:"changed_at" bson:"changed_at"`
-	ChangedBy primitive.ObjectID `json:"changed_by" bson:"changed_by"` // UserID of the admin who changed the status
 }
 
-type LoanUpdateInput struct {
-	Amount  float64 `json:"amount" bson:"amount"`
-	Term    int     `json:"term" bson:"term"` // In months
-	Purpose string  `json:"purpose" bson:"purpose"`
-	Status  string  `json:"status" bson:"status"` // "pending", "approved", "rejected"
+type LoanFieldChange struct {
+	Field    string `json:"field" bson:"field"` // "amount", "term", "purpose"
+	Previous string `json:"previous" bson:"previous"`
+	Current  string `json:"current" bson:"current"`
 }
 
 type LoanStatusUpdateInput struct {
-	Status    string             `json:"status" bson:"status"`         // "pending", "approved", "rejected"
-	ChangedBy primitive.ObjectID `json:"changed_by" bson:"changed_by"` // UserID of the admin who changed the status
+	Status    string             `json:"status" bson:"status"`         // Target status, one of LoanStatuses
+	ChangedBy primitive.ObjectID `json:"changed_by" bson:"changed_by"` // UserID of the user who changed the status
+	Roles     []string           `json:"-" bson:"-"`                   // Roles of the user who changed the status
+	Comment   string             `json:"comment" bson:"comment"`       // Reason for the change, kept in the status history
+
+	InterestRate   float64 `json:"interest_rate" bson:"interest_rate"`     // Used when approving loans without a product
+	InterestMethod string  `json:"interest_method" bson:"interest_method"` // Defaults to "reducing_balance"
 }
 
 type LoanInput struct {
-	UserID  primitive.ObjectID `json:"user_id" bson:"user_id"`
-	Amount  float64            `json:"amount" bson:"amount"`
-	Term    int                `json:"term" bson:"term"` // In months
-	Purpose string             `json:"purpose" bson:"purpose"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	ProductID primitive.ObjectID `json:"product_id" bson:"product_id"`
+	Amount    Money              `json:"amount" bson:"amount"`
+	Term      int                `json:"term" bson:"term"` // In months
+	Purpose   string             `json:"purpose" bson:"purpose"`
+	Draft     bool               `json:"draft" bson:"draft"` // Saves the application without submitting it
+}
+
+var LoanSortFields = []string{"created_at", "updated_at", "amount", "term", "status"}
+
+// LoanFilter selects a borrower's loans for listing.
+type LoanFilter struct {
+	UserID primitive.ObjectID // Required: owner of the loans
+	Status string             // Optional: one of LoanStatuses
+	SortBy string             // Optional: "created_at" (default), "updated_at", "amount", "term", "status"
+	Order  string             // Optional: "asc" or "desc" (default)
+	Page   int                // 1-based, defaults to 1
+	Limit  int                // Defaults to 20, at most 100
+}
+
+type LoanPage struct {
+	Loans []Loan `json:"loans"`
+	Total int64  `json:"total"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+// LoanSearch selects loans across all borrowers for the admin listing. Pages
+// are addressed by an opaque cursor rather than an offset so that deep pages
+// stay cheap on large collections.
+type LoanSearch struct {
+	UserID      primitive.ObjectID // Optional: owner of the loans
+	Status      string             // Optional: one of LoanStatuses
+	Currency    string             // Optional; required with an amount range
+	MinAmount   *Money             // Optional, inclusive
+	MaxAmount   *Money             // Optional, inclusive
+	Term        int                // Optional: exact term in months
+	Purpose     string             // Optional: case-insensitive text contained in the purpose
+	CreatedFrom *time.Time         // Optional, inclusive
+	CreatedTo   *time.Time         // Optional, exclusive
+	UpdatedFrom *time.Time         // Optional, inclusive
+	UpdatedTo   *time.Time         // Optional, exclusive
+	SortBy      string             // Optional: one of LoanSortFields, defaults to "created_at"
+	Order       string             // Optional: "asc" or "desc" (default)
+	Cursor      string             // Optional: next_cursor of the previous page
+	Limit       int                // Defaults to 20, at most 100
+}
+
+type LoanCursorPage struct {
+	Loans      []Loan `json:"loans"`
+	Total      int64  `json:"total"` // Loans matching the filters across all pages
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+type LoanReviewersInput struct {
+	ReviewerIDs []string           `json:"reviewer_ids" bson:"reviewer_ids"`
+	ChangedBy   primitive.ObjectID `json:"changed_by" bson:"changed_by"`
 }
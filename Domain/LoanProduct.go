@@ -0,0 +1,41 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoanProduct is one version of a loan product. Every change is saved as a new
+// version so loans keep the terms of the version they were originated under.
+type LoanProduct struct {
+	ID                primitive.ObjectID `json:"id" bson:"id"`           // Shared by all versions of the product
+	Version           int                `json:"version" bson:"version"` // Starts at 1
+	IsLatest          bool               `json:"is_latest" bson:"is_latest"`
+	Name              string             `json:"name" bson:"name"`
+	Currency          string             `json:"currency" bson:"currency"` // ISO 4217 code all amounts of the product are in
+	MinAmount         Money              `json:"min_amount" bson:"min_amount"`
+	MaxAmount         Money              `json:"max_amount" bson:"max_amount"`
+	AllowedTerms      []int              `json:"allowed_terms" bson:"allowed_terms"`     // In months
+	InterestRate      float64            `json:"interest_rate" bson:"interest_rate"`     // Annual nominal rate in percent
+	InterestMethod    string             `json:"interest_method" bson:"interest_method"` // "reducing_balance", "flat"
+	ProcessingFee     Money              `json:"processing_fee" bson:"processing_fee"`   // Flat fee added to the loan on approval
+	GracePeriodMonths int                `json:"grace_period_months" bson:"grace_period_months"`
+	IsActive          bool               `json:"is_active" bson:"is_active"` // Inactive products accept no new applications
+	CreatedBy         primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type LoanProductInput struct {
+	Name              string             `json:"name" bson:"name"`
+	Currency          string             `json:"currency" bson:"currency"` // ISO 4217 code all amounts of the product are in
+	MinAmount         Money              `json:"min_amount" bson:"min_amount"`
+	MaxAmount         Money              `json:"max_amount" bson:"max_amount"`
+	AllowedTerms      []int              `json:"allowed_terms" bson:"allowed_terms"`
+	InterestRate      float64            `json:"interest_rate" bson:"interest_rate"`
+	InterestMethod    string             `json:"interest_method" bson:"interest_method"`
+	ProcessingFee     Money              `json:"processing_fee" bson:"processing_fee"`
+	GracePeriodMonths int                `json:"grace_period_months" bson:"grace_period_months"`
+	IsActive          *bool              `json:"is_active" bson:"is_active"` // Defaults to true on creation
+	CreatedBy         primitive.ObjectID `json:"created_by" bson:"created_by"`
+}
@@ -0,0 +1,264 @@
+package Domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for amounts submitted without a currency code.
+const DefaultCurrency = "USD"
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// currencyExponents lists ISO 4217 currencies whose minor unit is not 1/100.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Money is an exact amount in the minor units (e.g. cents) of an ISO 4217 currency.
+// It is stored in Mongo as an int64 and serialized to JSON as a decimal string.
+type Money struct {
+	MinorUnits int64  `bson:"minor_units"`
+	Currency   string `bson:"currency"`
+}
+
+// NewMoney creates an amount from minor units.
+func NewMoney(minorUnits int64, currency string) Money {
+	return Money{MinorUnits: minorUnits, Currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "1250.50" in the given currency.
+// It rejects amounts with more fractional digits than the currency allows.
+func ParseMoney(amount string, currency string) (Money, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return Money{}, errors.New("amount is empty")
+	}
+	if currency != "" && !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("invalid currency %q", currency)
+	}
+
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	exponent := CurrencyExponent(currency)
+	if len(fraction) > exponent {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places", amount, exponent)
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	if whole == "" {
+		whole = "0"
+	}
+	minorUnits, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || strings.ContainsAny(whole+fraction, "+-") {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if negative {
+		minorUnits = -minorUnits
+	}
+	return Money{MinorUnits: minorUnits, Currency: currency}, nil
+}
+
+// IsValidCurrency reports whether code looks like an ISO 4217 currency code.
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// CurrencyExponent returns the number of decimal places of the currency's minor unit.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// Decimal formats the amount as a decimal string without the currency, e.g. "1250.50".
+func (m Money) Decimal() string {
+	exponent := CurrencyExponent(m.Currency)
+	units := m.MinorUnits
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	if exponent == 0 {
+		return sign + strconv.FormatInt(units, 10)
+	}
+
+	digits := fmt.Sprintf("%0*d", exponent+1, units)
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool     { return m.MinorUnits == 0 }
+func (m Money) IsPositive() bool { return m.MinorUnits > 0 }
+func (m Money) IsNegative() bool { return m.MinorUnits < 0 }
+
+// Add returns m + other. An empty currency on either side adopts the other's.
+// Add, Sub, Cmp and Min return ErrCurrencyMismatch for amounts in different currencies.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.mergeCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{MinorUnits: m.MinorUnits + other.MinorUnits, Currency: currency}, nil
+}
+
+// Sub returns m - other.
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.mergeCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{MinorUnits: m.MinorUnits - other.MinorUnits, Currency: currency}, nil
+}
+
+// Cmp returns -1, 0 or 1 depending on whether m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) (int, error) {
+	if _, err := m.mergeCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.MinorUnits < other.MinorUnits:
+		return -1, nil
+	case m.MinorUnits > other.MinorUnits:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Min returns the smaller of m and other.
+func (m Money) Min(other Money) (Money, error) {
+	cmp, err := m.Cmp(other)
+	if err != nil {
+		return Money{}, err
+	}
+	if cmp <= 0 {
+		return m, nil
+	}
+	return other, nil
+}
+
+// MulRate multiplies the amount by rate, rounding half away from zero to the minor unit.
+func (m Money) MulRate(rate float64) Money {
+	return Money{MinorUnits: int64(math.Round(float64(m.MinorUnits) * rate)), Currency: m.Currency}
+}
+
+// Div divides the amount into n parts, rounding half away from zero to the minor unit.
+func (m Money) Div(n int) (Money, error) {
+	if n <= 0 {
+		return Money{}, fmt.Errorf("cannot divide an amount into %d parts", n)
+	}
+	return m.MulRate(1 / float64(n)), nil
+}
+
+// WithCurrency assigns a currency to an amount that was parsed without one,
+// rescaling it to the currency's minor unit. Amounts that already carry a
+// currency must match it.
+func (m Money) WithCurrency(currency string) (Money, error) {
+	if !IsValidCurrency(currency) {
+		return Money{}, fmt.Errorf("invalid currency %q", currency)
+	}
+	if m.Currency != "" {
+		if m.Currency != currency {
+			return Money{}, fmt.Errorf("amount is in %s, expected %s", m.Currency, currency)
+		}
+		return m, nil
+	}
+
+	units := m.MinorUnits
+	for shift := CurrencyExponent(currency) - CurrencyExponent(""); shift != 0; {
+		if shift > 0 {
+			units *= 10
+			shift--
+			continue
+		}
+		if units%10 != 0 {
+			return Money{}, fmt.Errorf("amount has more than %d decimal places for %s", CurrencyExponent(currency), currency)
+		}
+		units /= 10
+		shift++
+	}
+	return Money{MinorUnits: units, Currency: currency}, nil
+}
+
+// Convert returns the amount in another currency using rate units of the target
+// currency per unit of m's currency, rounded to the target's minor unit.
+func (m Money) Convert(currency string, rate float64) Money {
+	scale := math.Pow10(CurrencyExponent(currency) - CurrencyExponent(m.Currency))
+	return Money{MinorUnits: int64(math.Round(float64(m.MinorUnits) * rate * scale)), Currency: currency}
+}
+
+// SameCurrency reports whether both amounts are in the same currency.
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+func (m Money) mergeCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as {"amount": "1250.50", "currency": "USD"}.
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, _ := json.Marshal(m.Decimal())
+	return json.Marshal(moneyJSON{Amount: amount, Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts {"amount": "1250.50", "currency": "USD"} or a bare
+// decimal string or number. A missing currency is left empty for the caller to fill.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value moneyJSON
+	if len(data) > 0 && data[0] == '{' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		value.Amount = data
+	}
+
+	amount := strings.Trim(string(value.Amount), `"`)
+	if amount == "" || amount == "null" {
+		*m = Money{Currency: value.Currency}
+		return nil
+	}
+
+	parsed, err := ParseMoney(amount, value.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,195 @@
+package Domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{name: "two decimals", amount: "1250.50", currency: "USD", want: NewMoney(125050, "USD")},
+		{name: "whole amount", amount: "1250", currency: "USD", want: NewMoney(125000, "USD")},
+		{name: "short fraction", amount: "0.5", currency: "USD", want: NewMoney(50, "USD")},
+		{name: "no whole part", amount: ".75", currency: "USD", want: NewMoney(75, "USD")},
+		{name: "negative", amount: "-12.34", currency: "USD", want: NewMoney(-1234, "USD")},
+		{name: "surrounding spaces", amount: " 10.00 ", currency: "USD", want: NewMoney(1000, "USD")},
+		{name: "no currency", amount: "10.5", currency: "", want: NewMoney(1050, "")},
+		{name: "exponent 0", amount: "1500", currency: "JPY", want: NewMoney(1500, "JPY")},
+		{name: "exponent 3", amount: "1.234", currency: "KWD", want: NewMoney(1234, "KWD")},
+		{name: "exponent 3 short fraction", amount: "1.2", currency: "KWD", want: NewMoney(1200, "KWD")},
+		{name: "empty", amount: "", currency: "USD", wantErr: true},
+		{name: "too many decimals", amount: "1.234", currency: "USD", wantErr: true},
+		{name: "decimals for exponent 0", amount: "1500.5", currency: "JPY", wantErr: true},
+		{name: "too many decimals for exponent 3", amount: "1.2345", currency: "KWD", wantErr: true},
+		{name: "explicit plus sign", amount: "+10", currency: "USD", wantErr: true},
+		{name: "double minus", amount: "--10", currency: "USD", wantErr: true},
+		{name: "minus after point", amount: "1.-5", currency: "USD", wantErr: true},
+		{name: "letters", amount: "12a", currency: "USD", wantErr: true},
+		{name: "overflow", amount: "92233720368547758.08", currency: "USD", wantErr: true},
+		{name: "invalid currency", amount: "10", currency: "usd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseMoney(%q, %q) = %v, want an error", tt.amount, tt.currency, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q, %q) returned error: %v", tt.amount, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q, %q) = %#v, want %#v", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(125050, "USD"), "1250.50"},
+		{NewMoney(5, "USD"), "0.05"},
+		{NewMoney(0, "USD"), "0.00"},
+		{NewMoney(-5, "USD"), "-0.05"},
+		{NewMoney(-125050, "USD"), "-1250.50"},
+		{NewMoney(1500, "JPY"), "1500"},
+		{NewMoney(-1500, "JPY"), "-1500"},
+		{NewMoney(1234, "KWD"), "1.234"},
+		{NewMoney(7, "KWD"), "0.007"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%#v.Decimal() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyWithCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{name: "same exponent", money: NewMoney(1234, ""), currency: "USD", want: NewMoney(1234, "USD")},
+		{name: "to exponent 3", money: NewMoney(1234, ""), currency: "KWD", want: NewMoney(12340, "KWD")},
+		{name: "to exponent 0", money: NewMoney(150000, ""), currency: "JPY", want: NewMoney(1500, "JPY")},
+		{name: "negative to exponent 0", money: NewMoney(-150000, ""), currency: "JPY", want: NewMoney(-1500, "JPY")},
+		{name: "fraction lost at exponent 0", money: NewMoney(1234, ""), currency: "JPY", wantErr: true},
+		{name: "already in the currency", money: NewMoney(1500, "JPY"), currency: "JPY", want: NewMoney(1500, "JPY")},
+		{name: "in another currency", money: NewMoney(1500, "USD"), currency: "EUR", wantErr: true},
+		{name: "invalid currency", money: NewMoney(1500, ""), currency: "EURO", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.WithCurrency(tt.currency)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("WithCurrency(%q) = %v, want an error", tt.currency, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WithCurrency(%q) returned error: %v", tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("WithCurrency(%q) = %#v, want %#v", tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		currency string
+		rate     float64
+		want     Money
+	}{
+		{name: "same exponent", money: NewMoney(10000, "USD"), currency: "EUR", rate: 0.92, want: NewMoney(9200, "EUR")},
+		{name: "to exponent 0", money: NewMoney(10000, "USD"), currency: "JPY", rate: 150, want: NewMoney(15000, "JPY")},
+		{name: "from exponent 0", money: NewMoney(1000, "JPY"), currency: "USD", rate: 0.0067, want: NewMoney(670, "USD")},
+		{name: "to exponent 3", money: NewMoney(10000, "USD"), currency: "KWD", rate: 0.307, want: NewMoney(30700, "KWD")},
+		{name: "rounds half away from zero", money: NewMoney(1, "USD"), currency: "EUR", rate: 0.5, want: NewMoney(1, "EUR")},
+		{name: "negative rounds half away from zero", money: NewMoney(-1, "USD"), currency: "EUR", rate: 0.5, want: NewMoney(-1, "EUR")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.money.Convert(tt.currency, tt.rate); got != tt.want {
+				t.Errorf("Convert(%q, %v) = %#v, want %#v", tt.currency, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := NewMoney(1000, "USD")
+	eur := NewMoney(500, "EUR")
+
+	sum, err := usd.Add(NewMoney(250, ""))
+	if err != nil || sum != NewMoney(1250, "USD") {
+		t.Errorf("Add with an empty currency = %#v, %v, want 12.50 USD", sum, err)
+	}
+	diff, err := NewMoney(250, "").Sub(usd)
+	if err != nil || diff != NewMoney(-750, "USD") {
+		t.Errorf("Sub from an empty currency = %#v, %v, want -7.50 USD", diff, err)
+	}
+	smaller, err := usd.Min(NewMoney(250, "USD"))
+	if err != nil || smaller != NewMoney(250, "USD") {
+		t.Errorf("Min = %#v, %v, want 2.50 USD", smaller, err)
+	}
+	for _, tt := range []struct {
+		other Money
+		want  int
+	}{
+		{NewMoney(999, "USD"), 1},
+		{NewMoney(1000, "USD"), 0},
+		{NewMoney(1001, "USD"), -1},
+	} {
+		if got, err := usd.Cmp(tt.other); err != nil || got != tt.want {
+			t.Errorf("Cmp(%v) = %d, %v, want %d", tt.other, got, err, tt.want)
+		}
+	}
+
+	if _, err := usd.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies returned %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub across currencies returned %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Cmp(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp across currencies returned %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Min(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Min across currencies returned %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestMoneyDiv(t *testing.T) {
+	got, err := NewMoney(10000, "USD").Div(3)
+	if err != nil || got != NewMoney(3333, "USD") {
+		t.Errorf("Div(3) = %#v, %v, want 33.33 USD", got, err)
+	}
+	for _, n := range []int{0, -1} {
+		if _, err := NewMoney(10000, "USD").Div(n); err == nil {
+			t.Errorf("Div(%d) returned no error", n)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Outbox message statuses.
+const (
+	OutboxStatusPending = "pending" // Waiting for its first or next attempt
+	OutboxStatusSending = "sending" // Claimed by a worker
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead" // Gave up after the last attempt; can be retried by an admin
+)
+
+var OutboxStatuses = []string{OutboxStatusPending, OutboxStatusSending, OutboxStatusSent, OutboxStatusDead}
+
+// OutboxMessage is an email waiting to be sent, or the record of one that was.
+// Emails are stored here first and delivered by the outbox worker so that a
+// slow or unreachable mail server never fails the request that sent them.
+type OutboxMessage struct {
+	ID            primitive.ObjectID `json:"id" bson:"id"`
+	Message       EmailMessage       `json:"message" bson:"message"`
+	Category      string             `json:"category" bson:"category"` // Template the email was rendered from
+	Status        string             `json:"status" bson:"status"`     // "pending", "sending", "sent", "dead"
+	Attempts      int                `json:"attempts" bson:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" bson:"next_attempt_at"`
+	LockedUntil   *time.Time         `json:"-" bson:"locked_until,omitempty"` // A worker that crashes while sending loses its claim after this
+	LastError     string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	SentAt        *time.Time         `json:"sent_at,omitempty" bson:"sent_at,omitempty"`
+}
+
+type OutboxRunResult struct {
+	Sent         int `json:"sent"`
+	Retrying     int `json:"retrying"`
+	DeadLettered int `json:"dead_lettered"`
+}
@@ -0,0 +1,40 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var PaymentChannels = []string{"cash", "bank_transfer", "mobile_money", "card", "cheque"}
+
+type Payment struct {
+	ID            primitive.ObjectID  `json:"id" bson:"id"`
+	LoanID        primitive.ObjectID  `json:"loan_id" bson:"loan_id"`
+	Amount        Money               `json:"amount" bson:"amount"`
+	Currency      string              `json:"currency" bson:"currency"` // Always the currency of the loan
+	PaidAt        time.Time           `json:"paid_at" bson:"paid_at"`
+	Channel       string              `json:"channel" bson:"channel"`     // "cash", "bank_transfer", "mobile_money", "card", "cheque"
+	Reference     string              `json:"reference" bson:"reference"` // Receipt or transaction reference from the channel
+	PrincipalPaid Money               `json:"principal_paid" bson:"principal_paid"`
+	InterestPaid  Money               `json:"interest_paid" bson:"interest_paid"`
+	FeesPaid      Money               `json:"fees_paid" bson:"fees_paid"`
+	Allocations   []PaymentAllocation `json:"allocations" bson:"allocations"`
+	RecordedBy    primitive.ObjectID  `json:"recorded_by" bson:"recorded_by"` // UserID of the admin who recorded the payment
+	CreatedAt     time.Time           `json:"created_at" bson:"created_at"`
+}
+
+// PaymentAllocation is the part of a payment applied to a single installment.
+type PaymentAllocation struct {
+	InstallmentNumber int   `json:"installment_number" bson:"installment_number"`
+	Principal         Money `json:"principal" bson:"principal"`
+	Interest          Money `json:"interest" bson:"interest"`
+}
+
+type PaymentInput struct {
+	Amount     Money              `json:"amount" bson:"amount"`
+	PaidAt     time.Time          `json:"paid_at" bson:"paid_at"` // Defaults to the time the payment is recorded
+	Channel    string             `json:"channel" bson:"channel"`
+	Reference  string             `json:"reference" bson:"reference"`
+	RecordedBy primitive.ObjectID `json:"recorded_by" bson:"recorded_by"`
+}
@@ -0,0 +1,62 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	LateFeeTypeFlat       = "flat"
+	LateFeeTypePercentage = "percentage"
+)
+
+const (
+	ChargeTypeLateFee         = "late_fee"
+	ChargeTypePenaltyInterest = "penalty_interest"
+)
+
+// PenaltyPolicy configures the late fees and penalty interest charged on
+// overdue installments of loans in one currency.
+type PenaltyPolicy struct {
+	ID            primitive.ObjectID `json:"id" bson:"id"`
+	Currency      string             `json:"currency" bson:"currency"`
+	LateFeeType   string             `json:"late_fee_type" bson:"late_fee_type"`     // "flat", "percentage" or empty for no late fee
+	LateFeeAmount Money              `json:"late_fee_amount" bson:"late_fee_amount"` // Charged once per overdue installment when flat
+	LateFeeRate   float64            `json:"late_fee_rate" bson:"late_fee_rate"`     // Percent of the installment's unpaid amount when percentage
+	PenaltyRate   float64            `json:"penalty_rate" bson:"penalty_rate"`       // Annual percent accrued daily on overdue principal, 0 for none
+	GraceDays     int                `json:"grace_days" bson:"grace_days"`           // Days after the due date before anything is charged
+	EffectiveFrom time.Time          `json:"effective_from" bson:"effective_from"`   // Nothing is charged for days before the policy was set
+	UpdatedBy     primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type PenaltyPolicyInput struct {
+	Currency      string             `json:"currency" bson:"currency"`
+	LateFeeType   string             `json:"late_fee_type" bson:"late_fee_type"`
+	LateFeeAmount Money              `json:"late_fee_amount" bson:"late_fee_amount"`
+	LateFeeRate   float64            `json:"late_fee_rate" bson:"late_fee_rate"`
+	PenaltyRate   float64            `json:"penalty_rate" bson:"penalty_rate"`
+	GraceDays     int                `json:"grace_days" bson:"grace_days"`
+	UpdatedBy     primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+}
+
+// LoanCharge is a late fee or penalty interest posted to a loan's outstanding fees.
+type LoanCharge struct {
+	ID                primitive.ObjectID `json:"id" bson:"id"`
+	LoanID            primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	Key               string             `json:"-" bson:"key"`     // Unique per loan so that a charge is never posted twice
+	Type              string             `json:"type" bson:"type"` // "late_fee", "penalty_interest"
+	InstallmentNumber int                `json:"installment_number,omitempty" bson:"installment_number,omitempty"`
+	Amount            Money              `json:"amount" bson:"amount"`
+	Reason            string             `json:"reason" bson:"reason"`
+	AssessedFor       time.Time          `json:"assessed_for" bson:"assessed_for"` // Day the sweep assessed the charge for
+	FeesPending       bool               `json:"-" bson:"fees_pending,omitempty"`  // Set until the charge is added to the loan's outstanding fees
+	CreatedAt         time.Time          `json:"created_at" bson:"created_at"`
+}
+
+type PenaltySweepResult struct {
+	AsOf          time.Time `json:"as_of"`
+	LoansAssessed int       `json:"loans_assessed"`
+	ChargesPosted int       `json:"charges_posted"`
+}
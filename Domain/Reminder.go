@@ -0,0 +1,71 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of repayment reminder. Upcoming and due-date reminders are
+// non-essential and respect the borrower's opt-out; overdue notices are always sent.
+const (
+	ReminderKindUpcoming = "upcoming"
+	ReminderKindDue      = "due"
+	ReminderKindOverdue  = "overdue"
+)
+
+// Reminder delivery statuses.
+const (
+	ReminderStatusSending    = "sending"
+	ReminderStatusQueued     = "queued"     // Handed to the email outbox, see MessageID for its delivery
+	ReminderStatusFailed     = "failed"     // Could not be rendered or queued; retried by the next run
+	ReminderStatusSuppressed = "suppressed" // The borrower opted out of non-essential emails
+)
+
+// ReminderSettings configures which repayment reminders are emailed to borrowers.
+type ReminderSettings struct {
+	DaysBefore       []int              `json:"days_before" bson:"days_before"`               // Days before a due date to send a reminder, e.g. [7, 3]
+	SendOnDueDate    bool               `json:"send_on_due_date" bson:"send_on_due_date"`     // Remind on the due date itself
+	SendOverdue      bool               `json:"send_overdue" bson:"send_overdue"`             // Notify once an installment is overdue
+	OverdueAfterDays int                `json:"overdue_after_days" bson:"overdue_after_days"` // Days past the due date before the overdue notice, at least 1
+	UpdatedBy        primitive.ObjectID `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt        time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// DefaultReminderSettings apply until an admin changes them.
+var DefaultReminderSettings = ReminderSettings{
+	DaysBefore:       []int{3},
+	SendOnDueDate:    true,
+	SendOverdue:      true,
+	OverdueAfterDays: 1,
+}
+
+// ReminderDelivery records one reminder email for an installment. Key is unique
+// so that the same reminder is never sent twice.
+type ReminderDelivery struct {
+	ID                primitive.ObjectID `json:"id" bson:"id"`
+	Key               string             `json:"-" bson:"key"` // Loan, installment, kind and days before
+	LoanID            primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	UserID            primitive.ObjectID `json:"user_id" bson:"user_id"`
+	InstallmentNumber int                `json:"installment_number" bson:"installment_number"`
+	DueDate           time.Time          `json:"due_date" bson:"due_date"`
+	Kind              string             `json:"kind" bson:"kind"` // "upcoming", "due", "overdue"
+	DaysBefore        int                `json:"days_before,omitempty" bson:"days_before,omitempty"`
+	Email             string             `json:"email" bson:"email"`
+	Status            string             `json:"status" bson:"status"`                             // "sending", "queued", "failed", "suppressed"
+	MessageID         primitive.ObjectID `json:"message_id,omitempty" bson:"message_id,omitempty"` // Outbox message of the email
+	Attempts          int                `json:"attempts" bson:"attempts"`
+	Error             string             `json:"error,omitempty" bson:"error,omitempty"`
+	UpdatedAt         time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type ReminderRunResult struct {
+	AsOf       time.Time `json:"as_of"`
+	Queued     int       `json:"queued"`
+	Suppressed int       `json:"suppressed"`
+	Failed     int       `json:"failed"`
+}
+
+type EmailPreferencesInput struct {
+	OptOutNonEssential bool `json:"opt_out_non_essential" bson:"opt_out_non_essential"`
+}
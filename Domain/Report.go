@@ -0,0 +1,85 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FXRate converts amounts from one currency into another for reporting.
+type FXRate struct {
+	ID           primitive.ObjectID `json:"id" bson:"id"`
+	FromCurrency string             `json:"from_currency" bson:"from_currency"`
+	ToCurrency   string             `json:"to_currency" bson:"to_currency"`
+	Rate         float64            `json:"rate" bson:"rate"` // Units of ToCurrency per unit of FromCurrency
+	UpdatedBy    primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type FXRateInput struct {
+	FromCurrency string             `json:"from_currency" bson:"from_currency"`
+	ToCurrency   string             `json:"to_currency" bson:"to_currency"`
+	Rate         float64            `json:"rate" bson:"rate"`
+	UpdatedBy    primitive.ObjectID `json:"updated_by" bson:"updated_by"`
+}
+
+// Intervals of the applications time series in the portfolio report.
+const (
+	ReportIntervalDay   = "day"
+	ReportIntervalWeek  = "week" // Weeks start on Monday
+	ReportIntervalMonth = "month"
+)
+
+var ReportIntervals = []string{ReportIntervalDay, ReportIntervalWeek, ReportIntervalMonth}
+
+// CurrencyTotal sums the loan book for a single currency.
+type CurrencyTotal struct {
+	Currency      string  `json:"currency" bson:"currency"`
+	LoanCount     int     `json:"loan_count" bson:"loan_count"`
+	Principal     Money   `json:"principal" bson:"principal"`           // Original amounts of the loans, whatever their status
+	Disbursed     Money   `json:"disbursed" bson:"disbursed"`           // Original amounts of the loans paid out to borrowers
+	Outstanding   Money   `json:"outstanding" bson:"outstanding"`       // Principal, interest and fees still owed on disbursed, active and defaulted loans
+	AverageAmount Money   `json:"average_amount" bson:"average_amount"` // Rounded to the minor unit
+	AverageTerm   float64 `json:"average_term" bson:"average_term"`     // In months
+}
+
+// StatusTotal sums the loans in one status and currency.
+type StatusTotal struct {
+	Status      string `json:"status" bson:"status"`
+	Currency    string `json:"currency" bson:"currency"`
+	LoanCount   int    `json:"loan_count" bson:"loan_count"`
+	Principal   Money  `json:"principal" bson:"principal"`
+	Outstanding Money  `json:"outstanding" bson:"outstanding"`
+}
+
+// ApplicationCount is the number of loans applied for in one period of the time series.
+type ApplicationCount struct {
+	PeriodStart time.Time `json:"period_start" bson:"period_start"`
+	Count       int       `json:"count" bson:"count"`
+}
+
+type ApplicationSeries struct {
+	Interval string             `json:"interval"` // One of ReportIntervals
+	From     time.Time          `json:"from"`     // Inclusive
+	To       time.Time          `json:"to"`       // Exclusive
+	Points   []ApplicationCount `json:"points"`   // One per period, including periods without applications
+}
+
+// PortfolioQuery selects the optional parts of the portfolio report.
+type PortfolioQuery struct {
+	ReportingCurrency string     // Optional: converts the totals with the FX rate table
+	Interval          string     // Optional: one of ReportIntervals, defaults to "month"
+	From              *time.Time // Optional: start of the applications series, defaults to a year before To
+	To                *time.Time // Optional: end of the applications series, defaults to now
+}
+
+type PortfolioReport struct {
+	Totals            []CurrencyTotal   `json:"totals"` // One entry per currency, never added together
+	ByStatus          []StatusTotal     `json:"by_status"`
+	ApprovalRate      float64           `json:"approval_rate"` // Share of decided loans that were approved, from 0 to 1
+	AverageTerm       float64           `json:"average_term"`  // In months, across all currencies
+	Applications      ApplicationSeries `json:"applications"`
+	ReportingCurrency string            `json:"reporting_currency,omitempty"`
+	Converted         *CurrencyTotal    `json:"converted,omitempty"` // All currencies converted with the FX rate table
+	GeneratedAt       time.Time         `json:"generated_at"`
+}
@@ -0,0 +1,74 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// How a loan was assigned to an officer.
+const (
+	AssignmentMethodManual     = "manual"
+	AssignmentMethodRoundRobin = "round_robin"
+)
+
+// ReviewStatuses are the statuses of the loans waiting in the review queue.
+var ReviewStatuses = []string{LoanStatusSubmitted, LoanStatusPending, LoanStatusUnderReview}
+
+// ReviewSettings configures how applications are assigned to loan officers and
+// how long they may wait for a decision.
+type ReviewSettings struct {
+	SLAHours   int                `json:"sla_hours" bson:"sla_hours"`     // Applications waiting longer than this since submission are flagged
+	AutoAssign bool               `json:"auto_assign" bson:"auto_assign"` // Assign new applications round-robin on submission
+	UpdatedBy  primitive.ObjectID `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt  time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// DefaultReviewSettings apply until an admin changes them.
+var DefaultReviewSettings = ReviewSettings{
+	SLAHours:   48,
+	AutoAssign: false,
+}
+
+// LoanAssignment names the officer responsible for reviewing a loan.
+type LoanAssignment struct {
+	OfficerID  primitive.ObjectID `json:"officer_id" bson:"officer_id"`
+	Method     string             `json:"method" bson:"method"`                               // "manual", "round_robin"
+	AssignedBy primitive.ObjectID `json:"assigned_by,omitempty" bson:"assigned_by,omitempty"` // Empty for automatic assignments
+	AssignedAt time.Time          `json:"assigned_at" bson:"assigned_at"`
+}
+
+// LoanAssignmentChange records one assignment or reassignment of a loan.
+type LoanAssignmentChange struct {
+	ID                primitive.ObjectID `json:"id" bson:"id"`
+	LoanID            primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	OfficerID         primitive.ObjectID `json:"officer_id" bson:"officer_id"`
+	PreviousOfficerID primitive.ObjectID `json:"previous_officer_id,omitempty" bson:"previous_officer_id,omitempty"`
+	Method            string             `json:"method" bson:"method"`
+	AssignedBy        primitive.ObjectID `json:"assigned_by,omitempty" bson:"assigned_by,omitempty"`
+	Reason            string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	AssignedAt        time.Time          `json:"assigned_at" bson:"assigned_at"`
+}
+
+// LoanAssignmentInput assigns a loan to OfficerID, or round-robin when RoundRobin is set.
+type LoanAssignmentInput struct {
+	OfficerID  string             `json:"officer_id" bson:"officer_id"`
+	RoundRobin bool               `json:"round_robin" bson:"round_robin"`
+	Reason     string             `json:"reason" bson:"reason"`
+	AssignedBy primitive.ObjectID `json:"assigned_by" bson:"assigned_by"`
+}
+
+// ReviewQueueFilter narrows the review queue; the zero value lists every waiting loan.
+type ReviewQueueFilter struct {
+	OfficerID   primitive.ObjectID
+	Unassigned  bool
+	OverdueOnly bool // Only loans waiting longer than the SLA
+}
+
+// ReviewQueueItem is a loan waiting for a decision and its SLA timer.
+type ReviewQueueItem struct {
+	Loan         Loan      `json:"loan"`
+	WaitingSince time.Time `json:"waiting_since"`
+	SLADueAt     time.Time `json:"sla_due_at"`
+	Overdue      bool      `json:"overdue"` // Waiting longer than the SLA
+}
@@ -0,0 +1,79 @@
+package Domain
+
+// Roles a user can be assigned. Every user is at least a borrower, who can
+// always see and manage their own loans; the other roles grant permissions
+// over everyone's records.
+const (
+	RoleBorrower      = "borrower"
+	RoleLoanOfficer   = "loan_officer"
+	RoleCreditManager = "credit_manager"
+	RoleAuditor       = "auditor"
+	RoleAdmin         = "admin"
+)
+
+var Roles = []string{RoleBorrower, RoleLoanOfficer, RoleCreditManager, RoleAuditor, RoleAdmin}
+
+// Permissions checked by the routes and use cases.
+const (
+	PermissionLoansRead         = "loans:read"          // View every loan and its history
+	PermissionLoansApprove      = "loans:approve"       // Review, approve, reject and close loans
+	PermissionLoansFinalApprove = "loans:final_approve" // Give the final approval on loans under dual control
+	PermissionLoansDelete       = "loans:delete"        // Delete loans
+	PermissionLoansAssign       = "loans:assign"        // Designate the reviewers of a loan
+	PermissionLoansDisburse     = "loans:disburse"      // Pay out approved loans
+	PermissionPaymentsRecord    = "payments:record"     // Record repayments
+	PermissionProductsManage    = "products:manage"     // Create, change and retire loan products
+	PermissionReportsRead       = "reports:read"        // Portfolio and delinquency reports, exports and FX rates
+	PermissionSettingsManage    = "settings:manage"     // FX rates, penalty policies, reminder settings and email templates
+	PermissionJobsRead          = "jobs:read"           // View background jobs and their runs
+	PermissionJobsRun           = "jobs:run"            // Run background jobs and sweeps on demand
+	PermissionEmailsManage      = "emails:manage"       // View the email outbox and retry dead-lettered emails
+	PermissionUsersRead         = "users:read"          // List users
+	PermissionUsersManage       = "users:manage"        // Delete users and assign roles
+	PermissionLogsRead          = "logs:read"           // Read the activity log
+)
+
+var Permissions = []string{
+	PermissionLoansRead, PermissionLoansApprove, PermissionLoansFinalApprove, PermissionLoansDelete, PermissionLoansAssign, PermissionLoansDisburse,
+	PermissionPaymentsRecord, PermissionProductsManage, PermissionReportsRead, PermissionSettingsManage,
+	PermissionJobsRead, PermissionJobsRun, PermissionEmailsManage, PermissionUsersRead, PermissionUsersManage,
+	PermissionLogsRead,
+}
+
+// RolePermissions maps every role to the permissions it grants.
+var RolePermissions = map[string][]string{
+	RoleBorrower: {},
+	RoleLoanOfficer: {
+		PermissionLoansRead, PermissionLoansApprove, PermissionPaymentsRecord,
+	},
+	RoleCreditManager: {
+		PermissionLoansRead, PermissionLoansApprove, PermissionLoansFinalApprove, PermissionLoansAssign,
+		PermissionLoansDisburse, PermissionPaymentsRecord, PermissionReportsRead,
+	},
+	RoleAuditor: {
+		PermissionLoansRead, PermissionReportsRead, PermissionJobsRead, PermissionUsersRead, PermissionLogsRead,
+	},
+	RoleAdmin: Permissions,
+}
+
+// HasPermission reports whether any of the roles grants the permission.
+func HasPermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, granted := range RolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RoleInfo describes a role and its permissions.
+type RoleInfo struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleAssignmentInput struct {
+	Roles []string `json:"roles"`
+}
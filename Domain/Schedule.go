@@ -0,0 +1,48 @@
+package Domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	InterestMethodReducingBalance = "reducing_balance"
+	InterestMethodFlat            = "flat"
+)
+
+const (
+	InstallmentStatusDue     = "due"
+	InstallmentStatusPartial = "partially_paid"
+	InstallmentStatusPaid    = "paid"
+)
+
+type Installment struct {
+	Number           int       `json:"number" bson:"number"`
+	DueDate          time.Time `json:"due_date" bson:"due_date"`
+	Principal        Money     `json:"principal" bson:"principal"`
+	Interest         Money     `json:"interest" bson:"interest"`
+	Total            Money     `json:"total" bson:"total"`                         // Principal + Interest
+	RemainingBalance Money     `json:"remaining_balance" bson:"remaining_balance"` // Principal still owed after this installment
+	PrincipalPaid    Money     `json:"principal_paid" bson:"principal_paid"`
+	InterestPaid     Money     `json:"interest_paid" bson:"interest_paid"`
+	Status           string    `json:"status" bson:"status"` // "due", "partially_paid", "paid"
+}
+
+type Schedule struct {
+	ID           primitive.ObjectID `json:"id" bson:"id"`
+	LoanID       primitive.ObjectID `json:"loan_id" bson:"loan_id"`
+	Method       string             `json:"method" bson:"method"`               // "reducing_balance", "flat"
+	InterestRate float64            `json:"interest_rate" bson:"interest_rate"` // Annual nominal rate in percent
+	Installments []Installment      `json:"installments" bson:"installments"`
+	GeneratedAt  time.Time          `json:"generated_at" bson:"generated_at"`
+}
+
+// Unpaid is the part of the installment the borrower still owes.
+func (i Installment) Unpaid() (Money, error) {
+	unpaid, err := i.Total.Sub(i.PrincipalPaid)
+	if err != nil {
+		return Money{}, err
+	}
+	return unpaid.Sub(i.InterestPaid)
+}
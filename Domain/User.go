@@ -1,6 +1,10 @@
 package Domain
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type User struct {
 	ID             primitive.ObjectID `json:"id" bson:"id"`
@@ -9,8 +13,14 @@ type User struct {
 	Password       string             `json:"password" bson:"password"`
 	Email          string             `json:"email" bson:"email"`
 	ProfilePicture string             `json:"profile_picture" bson:"profile_picture"`
-	Role           string             `json:"role" bson:"role"`
+	Roles          []string           `json:"roles" bson:"roles"` // See Roles
 	IsActive       bool               `json:"is_active" bson:"is_active"`
+	Locale         string             `json:"locale,omitempty" bson:"locale,omitempty"` // Language of the emails sent to the user, e.g. "fr"
+
+	OptOutNonEssential bool `json:"opt_out_non_essential" bson:"opt_out_non_essential"` // Skip payment reminders; overdue notices are still sent
+
+	VerificationEmailFailedAt *time.Time `json:"verification_email_failed_at,omitempty" bson:"verification_email_failed_at,omitempty"` // Set while the verification email still has to be resent
+	VerificationEmailAttempts int        `json:"verification_email_attempts,omitempty" bson:"verification_email_attempts,omitempty"`
 }
 
 type RegisterInput struct {
@@ -20,6 +30,7 @@ type RegisterInput struct {
 	Email          string `json:"email" bson:"email"`
 	ProfilePicture string `json:"profile_picture" bson:"profile_picture"`
 	Bio            string `json:"bio" bson:"bio"`
+	Locale         string `json:"locale" bson:"locale"`
 }
 
 type LoginInput struct {
@@ -34,3 +45,14 @@ type ChangePasswordInput struct {
 type ForgetPasswordInput struct {
 	Email string `json:"email" bson:"email"`
 }
+
+// Requester identifies the authenticated user making a request.
+type Requester struct {
+	UserID primitive.ObjectID
+	Roles  []string
+}
+
+// Can reports whether the requester's roles grant the permission.
+func (r Requester) Can(permission string) bool {
+	return HasPermission(r.Roles, permission)
+}
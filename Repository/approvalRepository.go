@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ApprovalRepository interface {
+	SavePolicy(policy *Domain.ApprovalPolicy) error
+	FindPolicy(currency string) (*Domain.ApprovalPolicy, error)
+	GetAllPolicies() ([]Domain.ApprovalPolicy, error)
+	DeletePolicy(currency string) (bool, error)
+	SaveLimit(limit *Domain.ApprovalLimit) error
+	GetAllLimits() ([]Domain.ApprovalLimit, error)
+	FindLimits(userID primitive.ObjectID, roles []string, currency string) ([]Domain.ApprovalLimit, error)
+	DeleteLimit(id primitive.ObjectID) (bool, error)
+	SaveStep(step *Domain.LoanApprovalStep) error
+	FindLatestStep(loanID primitive.ObjectID, action string) (*Domain.LoanApprovalStep, error)
+	FindStepsByLoanID(loanID primitive.ObjectID) ([]Domain.LoanApprovalStep, error)
+}
+
+type approvalRepository struct {
+	policyCollection *mongo.Collection
+	limitCollection  *mongo.Collection
+	stepCollection   *mongo.Collection
+}
+
+func NewApprovalRepository(policyCollection *mongo.Collection, limitCollection *mongo.Collection, stepCollection *mongo.Collection) ApprovalRepository {
+	return &approvalRepository{
+		policyCollection: policyCollection,
+		limitCollection:  limitCollection,
+		stepCollection:   stepCollection,
+	}
+}
+
+// SavePolicy stores the policy of a currency, replacing the previous one.
+func (r *approvalRepository) SavePolicy(policy *Domain.ApprovalPolicy) error {
+	filter := bson.M{"currency": policy.Currency}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.policyCollection.ReplaceOne(context.Background(), filter, policy, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save approval policy: %v", err)
+	}
+	return nil
+}
+
+// FindPolicy returns the policy of a currency, or nil if it has none.
+func (r *approvalRepository) FindPolicy(currency string) (*Domain.ApprovalPolicy, error) {
+	var policy Domain.ApprovalPolicy
+	err := r.policyCollection.FindOne(context.Background(), bson.M{"currency": currency}).Decode(&policy)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get approval policy: %v", err)
+	}
+	return &policy, nil
+}
+
+func (r *approvalRepository) GetAllPolicies() ([]Domain.ApprovalPolicy, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "currency", Value: 1}})
+	cursor, err := r.policyCollection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval policies: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	policies := []Domain.ApprovalPolicy{}
+	if err = cursor.All(context.Background(), &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse approval policies: %v", err)
+	}
+	return policies, nil
+}
+
+// DeletePolicy removes the policy of a currency and reports whether it existed.
+func (r *approvalRepository) DeletePolicy(currency string) (bool, error) {
+	result, err := r.policyCollection.DeleteOne(context.Background(), bson.M{"currency": currency})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete approval policy: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// SaveLimit stores the limit of a role or user in a currency, replacing the previous one.
+func (r *approvalRepository) SaveLimit(limit *Domain.ApprovalLimit) error {
+	filter := bson.M{"currency": limit.Currency, "role": limit.Role}
+	if limit.Role == "" {
+		filter = bson.M{"currency": limit.Currency, "user_id": limit.UserID}
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.limitCollection.ReplaceOne(context.Background(), filter, limit, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save approval limit: %v", err)
+	}
+	return nil
+}
+
+func (r *approvalRepository) GetAllLimits() ([]Domain.ApprovalLimit, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "currency", Value: 1}, {Key: "role", Value: 1}})
+	return r.findLimits(bson.M{}, opts)
+}
+
+// FindLimits retrieves the limits in a currency of the user and of any of their roles.
+func (r *approvalRepository) FindLimits(userID primitive.ObjectID, roles []string, currency string) ([]Domain.ApprovalLimit, error) {
+	filter := bson.M{
+		"currency": currency,
+		"$or":      bson.A{bson.M{"user_id": userID}, bson.M{"role": bson.M{"$in": roles}}},
+	}
+	return r.findLimits(filter, options.Find())
+}
+
+func (r *approvalRepository) findLimits(filter bson.M, opts *options.FindOptions) ([]Domain.ApprovalLimit, error) {
+	cursor, err := r.limitCollection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval limits: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	limits := []Domain.ApprovalLimit{}
+	if err = cursor.All(context.Background(), &limits); err != nil {
+		return nil, fmt.Errorf("failed to parse approval limits: %v", err)
+	}
+	return limits, nil
+}
+
+// DeleteLimit removes a limit and reports whether it existed.
+func (r *approvalRepository) DeleteLimit(id primitive.ObjectID) (bool, error) {
+	result, err := r.limitCollection.DeleteOne(context.Background(), bson.M{"id": id})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete approval limit: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *approvalRepository) SaveStep(step *Domain.LoanApprovalStep) error {
+	_, err := r.stepCollection.InsertOne(context.Background(), step)
+	if err != nil {
+		return fmt.Errorf("failed to save approval step: %v", err)
+	}
+	return nil
+}
+
+// FindLatestStep returns the most recent step of a loan with the given action, or nil if there is none.
+func (r *approvalRepository) FindLatestStep(loanID primitive.ObjectID, action string) (*Domain.LoanApprovalStep, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	var step Domain.LoanApprovalStep
+	err := r.stepCollection.FindOne(context.Background(), bson.M{"loan_id": loanID, "action": action}, opts).Decode(&step)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get approval step: %v", err)
+	}
+	return &step, nil
+}
+
+// FindStepsByLoanID retrieves the approval steps of a loan, oldest first.
+func (r *approvalRepository) FindStepsByLoanID(loanID primitive.ObjectID) ([]Domain.LoanApprovalStep, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.stepCollection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval steps: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	steps := []Domain.LoanApprovalStep{}
+	if err = cursor.All(context.Background(), &steps); err != nil {
+		return nil, fmt.Errorf("failed to parse approval steps: %v", err)
+	}
+	return steps, nil
+}
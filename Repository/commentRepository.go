@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository interface {
+	Save(comment *Domain.LoanComment) error
+	FindByID(loanID primitive.ObjectID, id primitive.ObjectID) (Domain.LoanComment, error)
+	FindByLoanID(loanID primitive.ObjectID, visibilities []string) ([]Domain.LoanComment, error)
+	UpdateBody(id primitive.ObjectID, body string, previous Domain.LoanCommentEdit) error
+	CreateIndexes() error
+}
+
+type commentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(collection *mongo.Collection) CommentRepository {
+	return &commentRepository{collection: collection}
+}
+
+func (r *commentRepository) Save(comment *Domain.LoanComment) error {
+	_, err := r.collection.InsertOne(context.Background(), comment)
+	if err != nil {
+		return fmt.Errorf("failed to save comment: %v", err)
+	}
+	return nil
+}
+
+// FindByID returns a comment of the given loan.
+func (r *commentRepository) FindByID(loanID primitive.ObjectID, id primitive.ObjectID) (Domain.LoanComment, error) {
+	var comment Domain.LoanComment
+	err := r.collection.FindOne(context.Background(), bson.M{"id": id, "loan_id": loanID}).Decode(&comment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Domain.LoanComment{}, fmt.Errorf("comment not found: %w", err)
+		}
+		return Domain.LoanComment{}, fmt.Errorf("failed to get comment: %v", err)
+	}
+	return comment, nil
+}
+
+// FindByLoanID retrieves the comments of a loan with one of the given visibilities, oldest first.
+func (r *commentRepository) FindByLoanID(loanID primitive.ObjectID, visibilities []string) ([]Domain.LoanComment, error) {
+	filter := bson.M{"loan_id": loanID, "visibility": bson.M{"$in": visibilities}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	comments := []Domain.LoanComment{}
+	if err = cursor.All(context.Background(), &comments); err != nil {
+		return nil, fmt.Errorf("failed to parse comments: %v", err)
+	}
+	return comments, nil
+}
+
+// UpdateBody replaces the body of a comment and appends the previous one to its edit history.
+func (r *commentRepository) UpdateBody(id primitive.ObjectID, body string, previous Domain.LoanCommentEdit) error {
+	update := bson.M{
+		"$set":  bson.M{"body": body, "edited_at": previous.EditedAt},
+		"$push": bson.M{"edits": previous},
+	}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update comment: %v", err)
+	}
+	return nil
+}
+
+// CreateIndexes makes sure the index used to read the thread of a loan exists.
+func (r *commentRepository) CreateIndexes() error {
+	index := mongo.IndexModel{Keys: bson.D{{Key: "loan_id", Value: 1}, {Key: "created_at", Value: 1}}}
+	if _, err := r.collection.Indexes().CreateOne(context.Background(), index); err != nil {
+		return fmt.Errorf("failed to create comment indexes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DelinquencyRepository interface {
+	Save(report *Domain.DelinquencyReport) error
+	FindByDate(asOf time.Time) (Domain.DelinquencyReport, error)
+}
+
+type delinquencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDelinquencyRepository(collection *mongo.Collection) DelinquencyRepository {
+	return &delinquencyRepository{
+		collection: collection,
+	}
+}
+
+// Save stores the classification of a day, replacing an earlier run of the same day.
+func (r *delinquencyRepository) Save(report *Domain.DelinquencyReport) error {
+	filter := bson.M{"as_of": report.AsOf}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(context.Background(), filter, report, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save delinquency snapshot: %v", err)
+	}
+	return nil
+}
+
+// FindByDate retrieves the classification stored for the day starting at asOf.
+func (r *delinquencyRepository) FindByDate(asOf time.Time) (Domain.DelinquencyReport, error) {
+	var report Domain.DelinquencyReport
+	err := r.collection.FindOne(context.Background(), bson.M{"as_of": asOf}).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return Domain.DelinquencyReport{}, fmt.Errorf("delinquency snapshot not found: %v", err)
+		}
+		return Domain.DelinquencyReport{}, fmt.Errorf("failed to find delinquency snapshot: %v", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDisbursementNotFound is returned when a loan has no disbursement record.
+var ErrDisbursementNotFound = errors.New("disbursement not found")
+
+type DisbursementRepository interface {
+	Claim(disbursement *Domain.Disbursement, staleAfter time.Duration) (bool, error)
+	Update(id primitive.ObjectID, fields bson.M) error
+	FindByLoanID(loanID primitive.ObjectID) (Domain.Disbursement, error)
+	CreateIndexes() error
+}
+
+type disbursementRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDisbursementRepository(collection *mongo.Collection) DisbursementRepository {
+	return &disbursementRepository{
+		collection: collection,
+	}
+}
+
+// Claim stores the disbursement of a loan and reports whether the caller may
+// go ahead with it. Only a failed disbursement, or a pending one that has not
+// been updated for staleAfter because its request stopped part way, can be
+// replaced; otherwise the upsert collides with the unique loan_id index and
+// the claim is refused, so concurrent requests cannot both pay out a loan.
+func (r *disbursementRepository) Claim(disbursement *Domain.Disbursement, staleAfter time.Duration) (bool, error) {
+	disbursement.UpdatedAt = time.Now()
+	filter := bson.M{
+		"loan_id": disbursement.LoanID,
+		"$or": bson.A{
+			bson.M{"status": Domain.DisbursementStatusFailed},
+			bson.M{"status": Domain.DisbursementStatusPending, "updated_at": bson.M{"$lt": disbursement.UpdatedAt.Add(-staleAfter)}},
+		},
+	}
+	_, err := r.collection.ReplaceOne(context.Background(), filter, disbursement, options.Replace().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to save disbursement: %v", err)
+	}
+	return true, nil
+}
+
+func (r *disbursementRepository) Update(id primitive.ObjectID, fields bson.M) error {
+	fields["updated_at"] = time.Now()
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"id": id}, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("failed to update disbursement: %v", err)
+	}
+	return nil
+}
+
+func (r *disbursementRepository) FindByLoanID(loanID primitive.ObjectID) (Domain.Disbursement, error) {
+	var disbursement Domain.Disbursement
+	err := r.collection.FindOne(context.Background(), bson.M{"loan_id": loanID}).Decode(&disbursement)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return Domain.Disbursement{}, ErrDisbursementNotFound
+		}
+		return Domain.Disbursement{}, fmt.Errorf("failed to find disbursement: %v", err)
+	}
+	return disbursement, nil
+}
+
+// CreateIndexes makes sure each loan has a single disbursement record.
+func (r *disbursementRepository) CreateIndexes() error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "loan_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := r.collection.Indexes().CreateOne(context.Background(), index); err != nil {
+		return fmt.Errorf("failed to create disbursement indexes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EmailTemplateRepository interface {
+	Save(tmpl *Domain.EmailTemplate) error
+	Find(name string, locale string) (*Domain.EmailTemplate, error)
+	GetAllTemplates() ([]Domain.EmailTemplate, error)
+	Delete(name string, locale string) (bool, error)
+}
+
+type emailTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailTemplateRepository(collection *mongo.Collection) EmailTemplateRepository {
+	return &emailTemplateRepository{
+		collection: collection,
+	}
+}
+
+// Save stores the override of a template for a locale, replacing the previous one.
+func (r *emailTemplateRepository) Save(tmpl *Domain.EmailTemplate) error {
+	filter := bson.M{"name": tmpl.Name, "locale": tmpl.Locale}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(context.Background(), filter, tmpl, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save email template: %v", err)
+	}
+	return nil
+}
+
+// Find returns the override of a template for a locale, or nil if there is none.
+func (r *emailTemplateRepository) Find(name string, locale string) (*Domain.EmailTemplate, error) {
+	var tmpl Domain.EmailTemplate
+	err := r.collection.FindOne(context.Background(), bson.M{"name": name, "locale": locale}).Decode(&tmpl)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email template: %v", err)
+	}
+	return &tmpl, nil
+}
+
+func (r *emailTemplateRepository) GetAllTemplates() ([]Domain.EmailTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "locale", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email templates: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	templates := []Domain.EmailTemplate{}
+	if err = cursor.All(context.Background(), &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse email templates: %v", err)
+	}
+	return templates, nil
+}
+
+// Delete removes an override and reports whether there was one.
+func (r *emailTemplateRepository) Delete(name string, locale string) (bool, error) {
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"name": name, "locale": locale})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete email template: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type FXRateRepository interface {
+	Save(rate *Domain.FXRate) error
+	FindRate(from string, to string) (Domain.FXRate, error)
+	GetAllRates() ([]Domain.FXRate, error)
+	Delete(from string, to string) (bool, error)
+}
+
+type fxRateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewFXRateRepository(collection *mongo.Collection) FXRateRepository {
+	return &fxRateRepository{
+		collection: collection,
+	}
+}
+
+// Save stores the rate for a currency pair, replacing the previous one.
+func (r *fxRateRepository) Save(rate *Domain.FXRate) error {
+	filter := bson.M{"from_currency": rate.FromCurrency, "to_currency": rate.ToCurrency}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(context.Background(), filter, rate, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save fx rate: %v", err)
+	}
+	return nil
+}
+
+func (r *fxRateRepository) FindRate(from string, to string) (Domain.FXRate, error) {
+	var rate Domain.FXRate
+	filter := bson.M{"from_currency": from, "to_currency": to}
+	err := r.collection.FindOne(context.Background(), filter).Decode(&rate)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return Domain.FXRate{}, fmt.Errorf("fx rate not found: %w", err)
+		}
+		return Domain.FXRate{}, fmt.Errorf("failed to find fx rate: %v", err)
+	}
+	return rate, nil
+}
+
+func (r *fxRateRepository) GetAllRates() ([]Domain.FXRate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "from_currency", Value: 1}, {Key: "to_currency", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fx rates: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var rates []Domain.FXRate
+	if err = cursor.All(context.Background(), &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse fx rates: %v", err)
+	}
+	return rates, nil
+}
+
+// Delete removes the rate of a currency pair and reports whether it existed.
+func (r *fxRateRepository) Delete(from string, to string) (bool, error) {
+	filter := bson.M{"from_currency": from, "to_currency": to}
+	result, err := r.collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete fx rate: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type JobRepository interface {
+	AcquireLease(job string, owner string, ttl time.Duration, slot time.Time) (bool, error)
+	RenewLease(job string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(job string, owner string) error
+	FindLease(job string) (*Domain.JobLease, error)
+	SaveRun(run *Domain.JobRun) error
+	FindRuns(job string, limit int) ([]Domain.JobRun, error)
+	CreateIndexes() error
+}
+
+type jobRepository struct {
+	leaseCollection *mongo.Collection
+	runCollection   *mongo.Collection
+}
+
+func NewJobRepository(leaseCollection *mongo.Collection, runCollection *mongo.Collection) JobRepository {
+	return &jobRepository{
+		leaseCollection: leaseCollection,
+		runCollection:   runCollection,
+	}
+}
+
+// AcquireLease takes the lease of a job for owner unless another replica holds
+// an unexpired one. A scheduled run passes its slot, the time it was scheduled
+// for, and is refused if that slot was already claimed; manual runs pass the
+// zero time. It relies on the unique index on job: when the lease is held or
+// the slot taken, the upsert tries to insert a second document and fails.
+func (r *jobRepository) AcquireLease(job string, owner string, ttl time.Duration, slot time.Time) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"job": job, "expires_at": bson.M{"$lte": now}}
+	set := bson.M{"owner": owner, "acquired_at": now, "expires_at": now.Add(ttl)}
+	if !slot.IsZero() {
+		filter["$or"] = bson.A{bson.M{"last_slot": bson.M{"$exists": false}}, bson.M{"last_slot": bson.M{"$lt": slot}}}
+		set["last_slot"] = slot
+	}
+	update := bson.M{"$set": set}
+
+	_, err := r.leaseCollection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to acquire job lease: %v", err)
+	}
+	return true, nil
+}
+
+// RenewLease extends a lease that owner still holds. It reports false if the
+// lease expired and was taken over in the meantime.
+func (r *jobRepository) RenewLease(job string, owner string, ttl time.Duration) (bool, error) {
+	filter := bson.M{"job": job, "owner": owner}
+	update := bson.M{"$set": bson.M{"expires_at": time.Now().Add(ttl)}}
+
+	result, err := r.leaseCollection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to renew job lease: %v", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// ReleaseLease expires a lease held by owner so that the job can run again immediately.
+func (r *jobRepository) ReleaseLease(job string, owner string) error {
+	filter := bson.M{"job": job, "owner": owner}
+	update := bson.M{"$set": bson.M{"expires_at": time.Now()}}
+
+	_, err := r.leaseCollection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to release job lease: %v", err)
+	}
+	return nil
+}
+
+// FindLease returns the unexpired lease of a job, or nil if no replica is running it.
+func (r *jobRepository) FindLease(job string) (*Domain.JobLease, error) {
+	var lease Domain.JobLease
+	filter := bson.M{"job": job, "expires_at": bson.M{"$gt": time.Now()}}
+	err := r.leaseCollection.FindOne(context.Background(), filter).Decode(&lease)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job lease: %v", err)
+	}
+	return &lease, nil
+}
+
+// SaveRun inserts or replaces a run in the job history.
+func (r *jobRepository) SaveRun(run *Domain.JobRun) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.runCollection.ReplaceOne(context.Background(), bson.M{"id": run.ID}, run, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save job run: %v", err)
+	}
+	return nil
+}
+
+// FindRuns retrieves the most recent runs of a job, newest first.
+func (r *jobRepository) FindRuns(job string, limit int) ([]Domain.JobRun, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.runCollection.Find(context.Background(), bson.M{"job": job}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	runs := []Domain.JobRun{}
+	if err = cursor.All(context.Background(), &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse job runs: %v", err)
+	}
+	return runs, nil
+}
+
+// CreateIndexes makes sure each job has a single lease document and keeps the
+// history lookups cheap.
+func (r *jobRepository) CreateIndexes() error {
+	lease := mongo.IndexModel{
+		Keys:    bson.D{{Key: "job", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := r.leaseCollection.Indexes().CreateOne(context.Background(), lease); err != nil {
+		return fmt.Errorf("failed to create job lease indexes: %v", err)
+	}
+
+	run := mongo.IndexModel{Keys: bson.D{{Key: "job", Value: 1}, {Key: "started_at", Value: -1}}}
+	if _, err := r.runCollection.Indexes().CreateOne(context.Background(), run); err != nil {
+		return fmt.Errorf("failed to create job run indexes: %v", err)
+	}
+	return nil
+}
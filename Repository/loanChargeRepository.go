@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrDuplicateCharge is returned when a charge with the same key was already posted to the loan.
+var ErrDuplicateCharge = errors.New("charge already posted")
+
+type LoanChargeRepository interface {
+	Save(charge *Domain.LoanCharge) error
+	MarkApplied(id primitive.ObjectID) error
+	FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanCharge, error)
+	CreateIndexes() error
+}
+
+type loanChargeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoanChargeRepository(collection *mongo.Collection) LoanChargeRepository {
+	return &loanChargeRepository{
+		collection: collection,
+	}
+}
+
+// Save stores a charge, or returns ErrDuplicateCharge if its key was already used for the loan.
+func (r *loanChargeRepository) Save(charge *Domain.LoanCharge) error {
+	_, err := r.collection.InsertOne(context.Background(), charge)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrDuplicateCharge
+		}
+		return fmt.Errorf("failed to save loan charge: %v", err)
+	}
+	return nil
+}
+
+// MarkApplied records that a charge was added to the loan's outstanding fees.
+func (r *loanChargeRepository) MarkApplied(id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"id": id}, bson.M{"$unset": bson.M{"fees_pending": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to update loan charge: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the charges of a loan in the order they were posted.
+func (r *loanChargeRepository) FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanCharge, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan charges: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	charges := []Domain.LoanCharge{}
+	if err = cursor.All(context.Background(), &charges); err != nil {
+		return nil, fmt.Errorf("failed to parse loan charges: %v", err)
+	}
+	return charges, nil
+}
+
+// CreateIndexes makes sure a charge key can only be used once per loan.
+func (r *loanChargeRepository) CreateIndexes() error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "loan_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := r.collection.Indexes().CreateOne(context.Background(), index)
+	if err != nil {
+		return fmt.Errorf("failed to create loan charge indexes: %v", err)
+	}
+	return nil
+}
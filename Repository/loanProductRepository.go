@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoanProductRepository interface {
+	SaveVersion(product *Domain.LoanProduct) error
+	FindByID(id primitive.ObjectID) (Domain.LoanProduct, error)
+	FindVersion(id primitive.ObjectID, version int) (Domain.LoanProduct, error)
+	GetAllProducts(activeOnly bool) ([]Domain.LoanProduct, error)
+	Deactivate(id primitive.ObjectID) error
+	CreateIndexes() error
+}
+
+type loanProductRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoanProductRepository(collection *mongo.Collection) LoanProductRepository {
+	return &loanProductRepository{
+		collection: collection,
+	}
+}
+
+// SaveVersion stores a new version of a product and marks it as the latest one.
+// The new version is inserted before the older ones are retired, so a failed
+// insert leaves the previous version in place instead of no latest version.
+func (r *loanProductRepository) SaveVersion(product *Domain.LoanProduct) error {
+	product.IsLatest = true
+	_, err := r.collection.InsertOne(context.Background(), product)
+	if err != nil {
+		return fmt.Errorf("failed to save product: %v", err)
+	}
+
+	filter := bson.M{"id": product.ID, "is_latest": true, "version": bson.M{"$lt": product.Version}}
+	_, err = r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"is_latest": false}})
+	if err != nil {
+		return fmt.Errorf("failed to update previous product version: %v", err)
+	}
+	return nil
+}
+
+// FindByID retrieves the latest version of a product. Between the two writes
+// of SaveVersion two versions are flagged as latest, so the highest one wins.
+func (r *loanProductRepository) FindByID(id primitive.ObjectID) (Domain.LoanProduct, error) {
+	return r.findOne(bson.M{"id": id, "is_latest": true})
+}
+
+// FindVersion retrieves a specific version of a product.
+func (r *loanProductRepository) FindVersion(id primitive.ObjectID, version int) (Domain.LoanProduct, error) {
+	return r.findOne(bson.M{"id": id, "version": version})
+}
+
+func (r *loanProductRepository) findOne(filter bson.M) (Domain.LoanProduct, error) {
+	var product Domain.LoanProduct
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err := r.collection.FindOne(context.Background(), filter, opts).Decode(&product)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return Domain.LoanProduct{}, fmt.Errorf("product not found: %v", err)
+		}
+		return Domain.LoanProduct{}, fmt.Errorf("failed to find product: %v", err)
+	}
+	return product, nil
+}
+
+// GetAllProducts retrieves the latest version of every product.
+func (r *loanProductRepository) GetAllProducts(activeOnly bool) ([]Domain.LoanProduct, error) {
+	filter := bson.M{"is_latest": true}
+	if activeOnly {
+		filter["is_active"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var products []Domain.LoanProduct
+	if err = cursor.All(context.Background(), &products); err != nil {
+		return nil, fmt.Errorf("failed to parse products: %v", err)
+	}
+	return products, nil
+}
+
+// Deactivate stops a product from accepting new applications. Its versions are
+// kept because existing loans still reference them.
+func (r *loanProductRepository) Deactivate(id primitive.ObjectID) error {
+	filter := bson.M{"id": id, "is_latest": true}
+	result, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"is_active": false}})
+	if err != nil {
+		return fmt.Errorf("failed to deactivate product: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("product not found")
+	}
+	return nil
+}
+
+// CreateIndexes makes sure a product cannot get the same version twice, so
+// concurrent edits of one product collide instead of both becoming the latest.
+func (r *loanProductRepository) CreateIndexes() error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := r.collection.Indexes().CreateOne(context.Background(), index); err != nil {
+		return fmt.Errorf("failed to create product indexes: %v", err)
+	}
+	return nil
+}
@@ -3,7 +3,13 @@ package repository
 import (
 	"Loan_Tracker/Domain"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,9 +20,21 @@ import (
 type LoanRepository interface {
 	Save(loan *Domain.Loan) error
 	FindByID(id primitive.ObjectID) (Domain.Loan, error)
-	GetAllLoans(status string, order string) ([]Domain.Loan, error)
+	SearchLoans(search Domain.LoanSearch) ([]Domain.Loan, int64, string, error)
+	StreamLoans(search Domain.LoanSearch, fn func(loan Domain.Loan) error) error
+	FindByUserID(filter Domain.LoanFilter) ([]Domain.Loan, int64, error)
 	UpdateStatus(status *Domain.LoanStatus) error
+	Update(id primitive.ObjectID, fields bson.M) error
+	UpdateDelinquency(classifications []Domain.LoanDelinquency, classifiedAt time.Time) error
+	ApplyCharge(id primitive.ObjectID, key string, amount Domain.Money) (bool, error)
+	TotalsByCurrency() ([]Domain.CurrencyTotal, error)
+	TotalsByStatus() ([]Domain.StatusTotal, error)
+	CountApplications(from time.Time, to time.Time, interval string) ([]Domain.ApplicationCount, error)
+	FindEscalated() ([]Domain.Loan, error)
+	FindInReview(filter Domain.ReviewQueueFilter) ([]Domain.Loan, error)
+	FlagReviewSLABreaches(submittedBefore time.Time, at time.Time) (int64, error)
 	Delete(id primitive.ObjectID) error
+	CreateIndexes() error
 }
 
 type loanRepository struct {
@@ -43,43 +61,255 @@ func (r *loanRepository) FindByID(id primitive.ObjectID) (Domain.Loan, error) {
 	err := r.collection.FindOne(context.Background(), filter).Decode(&loan)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return Domain.Loan{}, fmt.Errorf("loan not found: %v", err)
+			return Domain.Loan{}, fmt.Errorf("loan not found: %w", err)
 		}
 		return Domain.Loan{}, fmt.Errorf("failed to find loan: %v", err)
 	}
 	return loan, nil
 }
 
-func (r *loanRepository) GetAllLoans(status string, order string) ([]Domain.Loan, error) {
-	var filter bson.M
-	if status != "" {
-		filter = bson.M{"status": status}
+// loanSortFields maps the sort keys accepted by the API to document fields.
+var loanSortFields = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"amount":     "amount.minor_units",
+	"term":       "term",
+	"status":     "status",
+}
+
+// FindByUserID retrieves one page of a borrower's loans along with the total number of matches.
+func (r *loanRepository) FindByUserID(filter Domain.LoanFilter) ([]Domain.Loan, int64, error) {
+	query := bson.M{"user_id": filter.UserID}
+	if filter.Status != "" {
+		query["status"] = filter.Status
 	}
 
-	// Determine sort order based on status and user input
-	sortOrder := 1 // Default ascending order
-	if status == "approved" || status == "rejected" {
-		sortOrder = -1 // Descending order for reviewed statuses
-	} else if order == "desc" {
-		sortOrder = -1 // User-specified descending order
+	total, err := r.collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count loans: %v", err)
 	}
 
-	// Set sorting options
-	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: sortOrder}})
+	sortField, ok := loanSortFields[filter.SortBy]
+	if !ok {
+		sortField = "created_at"
+	}
+	sortOrder := -1
+	if filter.Order == "asc" {
+		sortOrder = 1
+	}
 
-	// Execute the query
-	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "id", Value: sortOrder}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := r.collection.Find(context.Background(), query, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get loans: %v", err)
+		return nil, 0, fmt.Errorf("failed to get loans: %v", err)
 	}
 	defer cursor.Close(context.Background())
 
-	// Parse results
-	var loans []Domain.Loan
+	loans := []Domain.Loan{}
 	if err = cursor.All(context.Background(), &loans); err != nil {
-		return nil, fmt.Errorf("failed to parse loans: %v", err)
+		return nil, 0, fmt.Errorf("failed to parse loans: %v", err)
 	}
-	return loans, nil
+	return loans, total, nil
+}
+
+// loanCursor is the position after the last loan of a page: its sort value and ID.
+type loanCursor struct {
+	SortBy string             `bson:"s"`
+	Value  bson.RawValue      `bson:"v"`
+	ID     primitive.ObjectID `bson:"id"`
+}
+
+func encodeLoanCursor(cursor loanCursor) (string, error) {
+	data, err := bson.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeLoanCursor(token string) (loanCursor, error) {
+	var cursor loanCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return loanCursor{}, errors.New("invalid cursor")
+	}
+	if err := bson.Unmarshal(data, &cursor); err != nil {
+		return loanCursor{}, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// loanSearchQuery translates the admin filters into a Mongo query.
+func loanSearchQuery(search Domain.LoanSearch) bson.M {
+	query := bson.M{}
+	if !search.UserID.IsZero() {
+		query["user_id"] = search.UserID
+	}
+	if search.Status != "" {
+		query["status"] = search.Status
+	}
+	if search.Currency != "" {
+		query["currency"] = search.Currency
+	}
+	if search.Term > 0 {
+		query["term"] = search.Term
+	}
+	if search.Purpose != "" {
+		query["purpose"] = primitive.Regex{Pattern: regexp.QuoteMeta(search.Purpose), Options: "i"}
+	}
+
+	amount := bson.M{}
+	if search.MinAmount != nil {
+		amount["$gte"] = search.MinAmount.MinorUnits
+	}
+	if search.MaxAmount != nil {
+		amount["$lte"] = search.MaxAmount.MinorUnits
+	}
+	if len(amount) > 0 {
+		query["amount.minor_units"] = amount
+	}
+
+	dateRange := func(field string, from, to *time.Time) {
+		bounds := bson.M{}
+		if from != nil {
+			bounds["$gte"] = *from
+		}
+		if to != nil {
+			bounds["$lt"] = *to
+		}
+		if len(bounds) > 0 {
+			query[field] = bounds
+		}
+	}
+	dateRange("created_at", search.CreatedFrom, search.CreatedTo)
+	dateRange("updated_at", search.UpdatedFrom, search.UpdatedTo)
+
+	return query
+}
+
+// loanSearchSort resolves the sort key, document field and direction of a search.
+func loanSearchSort(search Domain.LoanSearch) (string, string, int, error) {
+	sortKey := search.SortBy
+	if sortKey == "" {
+		sortKey = "created_at"
+	}
+	sortField, ok := loanSortFields[sortKey]
+	if !ok {
+		return "", "", 0, fmt.Errorf("invalid sort field %q", search.SortBy)
+	}
+	if search.Order == "asc" {
+		return sortKey, sortField, 1, nil
+	}
+	return sortKey, sortField, -1, nil
+}
+
+// SearchLoans retrieves one page of loans matching the admin filters, the total
+// number of matches and the cursor of the next page, which is empty on the last page.
+func (r *loanRepository) SearchLoans(search Domain.LoanSearch) ([]Domain.Loan, int64, string, error) {
+	query := loanSearchQuery(search)
+
+	total, err := r.collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to count loans: %v", err)
+	}
+
+	sortKey, sortField, sortOrder, err := loanSearchSort(search)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	comparison := "$lt"
+	if sortOrder == 1 {
+		comparison = "$gt"
+	}
+
+	if search.Cursor != "" {
+		after, err := decodeLoanCursor(search.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if after.SortBy != sortKey {
+			return nil, 0, "", errors.New("cursor does not match the requested sort")
+		}
+		query = bson.M{"$and": bson.A{query, bson.M{"$or": bson.A{
+			bson.M{sortField: bson.M{comparison: after.Value}},
+			bson.M{sortField: after.Value, "id": bson.M{comparison: after.ID}},
+		}}}}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "id", Value: sortOrder}}).
+		SetLimit(int64(search.Limit + 1)) // One extra loan tells whether there is a next page
+
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to get loans: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	loans := []Domain.Loan{}
+	var last loanCursor
+	for len(loans) < search.Limit && cursor.Next(context.Background()) {
+		var loan Domain.Loan
+		if err := cursor.Decode(&loan); err != nil {
+			return nil, 0, "", fmt.Errorf("failed to parse loans: %v", err)
+		}
+		value, err := cursor.Current.LookupErr(strings.Split(sortField, ".")...)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("failed to read sort value of loan %s: %v", loan.ID.Hex(), err)
+		}
+		last = loanCursor{SortBy: sortKey, Value: value, ID: loan.ID}
+		loans = append(loans, loan)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, 0, "", fmt.Errorf("failed to get loans: %v", err)
+	}
+
+	nextCursor := ""
+	if len(loans) == search.Limit && cursor.Next(context.Background()) {
+		nextCursor, err = encodeLoanCursor(last)
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return loans, total, nextCursor, nil
+}
+
+// StreamLoans calls fn for every loan matching the admin filters, reading them
+// from a Mongo cursor so that the whole result never sits in memory. The
+// search's cursor and limit are ignored.
+func (r *loanRepository) StreamLoans(search Domain.LoanSearch, fn func(loan Domain.Loan) error) error {
+	_, sortField, sortOrder, err := loanSearchSort(search)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: sortOrder}, {Key: "id", Value: sortOrder}}).
+		SetBatchSize(500)
+
+	cursor, err := r.collection.Find(context.Background(), loanSearchQuery(search), opts)
+	if err != nil {
+		return fmt.Errorf("failed to get loans: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var loan Domain.Loan
+		if err := cursor.Decode(&loan); err != nil {
+			return fmt.Errorf("failed to parse loan: %v", err)
+		}
+		if err := fn(loan); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to get loans: %v", err)
+	}
+	return nil
 }
 
 func (r *loanRepository) UpdateStatus(status *Domain.LoanStatus) error {
@@ -92,6 +322,251 @@ func (r *loanRepository) UpdateStatus(status *Domain.LoanStatus) error {
 	return nil
 }
 
+func (r *loanRepository) Update(id primitive.ObjectID, fields bson.M) error {
+	filter := bson.M{"id": id}
+	_, err := r.collection.UpdateOne(context.Background(), filter, bson.M{"$set": fields})
+	if err != nil {
+		return fmt.Errorf("failed to update loan: %v", err)
+	}
+	return nil
+}
+
+// ApplyCharge adds a posted charge to a loan's outstanding fees in place, so
+// that concurrent payments are not overwritten, and records its key in the
+// same update. It reports false if the charge was already applied.
+func (r *loanRepository) ApplyCharge(id primitive.ObjectID, key string, amount Domain.Money) (bool, error) {
+	filter := bson.M{"id": id, "applied_charges": bson.M{"$ne": key}}
+	update := bson.M{
+		"$inc":  bson.M{"outstanding_fees.minor_units": amount.MinorUnits},
+		"$set":  bson.M{"outstanding_fees.currency": amount.Currency, "updated_at": time.Now()},
+		"$push": bson.M{"applied_charges": key},
+	}
+	result, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to add loan fees: %v", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UpdateDelinquency stores the days past due and bucket of many loans in one round trip.
+func (r *loanRepository) UpdateDelinquency(classifications []Domain.LoanDelinquency, classifiedAt time.Time) error {
+	if len(classifications) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(classifications))
+	for _, classification := range classifications {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": classification.LoanID}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"days_past_due":      classification.DaysPastDue,
+				"delinquency_bucket": classification.Bucket,
+				"classified_at":      classifiedAt,
+			}}))
+	}
+
+	_, err := r.collection.BulkWrite(context.Background(), models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("failed to update loan delinquency: %v", err)
+	}
+	return nil
+}
+
+// loanOutstanding adds up the balances still owed on a loan inside an aggregation.
+// Only disbursed, active and defaulted loans count; written off, cancelled and
+// paid off loans keep their last balances on the document but owe nothing.
+var loanOutstanding = bson.M{"$cond": bson.A{
+	bson.M{"$in": bson.A{"$status", bson.A{Domain.LoanStatusDisbursed, Domain.LoanStatusActive, Domain.LoanStatusDefaulted}}},
+	bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$outstanding_principal.minor_units", 0}},
+		bson.M{"$ifNull": bson.A{"$outstanding_interest.minor_units", 0}},
+		bson.M{"$ifNull": bson.A{"$outstanding_fees.minor_units", 0}},
+	}},
+	0,
+}}
+
+// TotalsByCurrency sums loan amounts and balances separately for each currency.
+func (r *loanRepository) TotalsByCurrency() ([]Domain.CurrencyTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$currency",
+			"loan_count":  bson.M{"$sum": 1},
+			"principal":   bson.M{"$sum": "$amount.minor_units"},
+			"outstanding": bson.M{"$sum": loanOutstanding},
+			"disbursed": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$ifNull": bson.A{"$disbursed_at", false}}, "$amount.minor_units", 0,
+			}}},
+			"average_amount": bson.M{"$avg": "$amount.minor_units"},
+			"average_term":   bson.M{"$avg": "$term"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate loans: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var rows []struct {
+		Currency      string  `bson:"_id"`
+		LoanCount     int     `bson:"loan_count"`
+		Principal     int64   `bson:"principal"`
+		Outstanding   int64   `bson:"outstanding"`
+		Disbursed     int64   `bson:"disbursed"`
+		AverageAmount float64 `bson:"average_amount"`
+		AverageTerm   float64 `bson:"average_term"`
+	}
+	if err = cursor.All(context.Background(), &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse loan totals: %v", err)
+	}
+
+	totals := make([]Domain.CurrencyTotal, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, Domain.CurrencyTotal{
+			Currency:      row.Currency,
+			LoanCount:     row.LoanCount,
+			Principal:     Domain.NewMoney(row.Principal, row.Currency),
+			Disbursed:     Domain.NewMoney(row.Disbursed, row.Currency),
+			Outstanding:   Domain.NewMoney(row.Outstanding, row.Currency),
+			AverageAmount: Domain.NewMoney(int64(math.Round(row.AverageAmount)), row.Currency),
+			AverageTerm:   row.AverageTerm,
+		})
+	}
+	return totals, nil
+}
+
+// TotalsByStatus counts and sums the loans of every status, separately for each currency.
+func (r *loanRepository) TotalsByStatus() ([]Domain.StatusTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":         bson.M{"status": "$status", "currency": "$currency"},
+			"loan_count":  bson.M{"$sum": 1},
+			"principal":   bson.M{"$sum": "$amount.minor_units"},
+			"outstanding": bson.M{"$sum": loanOutstanding},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.status", Value: 1}, {Key: "_id.currency", Value: 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate loans: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var rows []struct {
+		Key struct {
+			Status   string `bson:"status"`
+			Currency string `bson:"currency"`
+		} `bson:"_id"`
+		LoanCount   int   `bson:"loan_count"`
+		Principal   int64 `bson:"principal"`
+		Outstanding int64 `bson:"outstanding"`
+	}
+	if err = cursor.All(context.Background(), &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse loan status totals: %v", err)
+	}
+
+	totals := make([]Domain.StatusTotal, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, Domain.StatusTotal{
+			Status:      row.Key.Status,
+			Currency:    row.Key.Currency,
+			LoanCount:   row.LoanCount,
+			Principal:   Domain.NewMoney(row.Principal, row.Key.Currency),
+			Outstanding: Domain.NewMoney(row.Outstanding, row.Key.Currency),
+		})
+	}
+	return totals, nil
+}
+
+// CountApplications counts the loans created in [from, to) per day, week or
+// month in UTC. Periods without applications are left out.
+func (r *loanRepository) CountApplications(from time.Time, to time.Time, interval string) ([]Domain.ApplicationCount, error) {
+	period := bson.M{"date": "$created_at", "unit": interval, "timezone": "UTC"}
+	if interval == Domain.ReportIntervalWeek {
+		period["startOfWeek"] = "monday"
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$dateTrunc": period},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$project", Value: bson.M{"_id": 0, "period_start": "$_id", "count": 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate loan applications: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	counts := []Domain.ApplicationCount{}
+	if err = cursor.All(context.Background(), &counts); err != nil {
+		return nil, fmt.Errorf("failed to parse loan applications: %v", err)
+	}
+	return counts, nil
+}
+
+// FindEscalated retrieves the loans under review that were escalated for
+// approval by someone with higher authority, longest waiting first.
+func (r *loanRepository) FindEscalated() ([]Domain.Loan, error) {
+	filter := bson.M{"status": Domain.LoanStatusUnderReview, "escalation": bson.M{"$exists": true}}
+	opts := options.Find().SetSort(bson.D{{Key: "escalation.escalated_at", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalated loans: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	loans := []Domain.Loan{}
+	if err = cursor.All(context.Background(), &loans); err != nil {
+		return nil, fmt.Errorf("failed to parse escalated loans: %v", err)
+	}
+	return loans, nil
+}
+
+// FindInReview retrieves the loans waiting for a decision, oldest application first.
+func (r *loanRepository) FindInReview(filter Domain.ReviewQueueFilter) ([]Domain.Loan, error) {
+	query := bson.M{"status": bson.M{"$in": Domain.ReviewStatuses}}
+	if !filter.OfficerID.IsZero() {
+		query["assignment.officer_id"] = filter.OfficerID
+	}
+	if filter.Unassigned {
+		query["assignment"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loans in review: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	loans := []Domain.Loan{}
+	if err = cursor.All(context.Background(), &loans); err != nil {
+		return nil, fmt.Errorf("failed to parse loans in review: %v", err)
+	}
+	return loans, nil
+}
+
+// FlagReviewSLABreaches marks the loans still waiting for a decision that were
+// submitted before submittedBefore and returns how many were newly flagged.
+func (r *loanRepository) FlagReviewSLABreaches(submittedBefore time.Time, at time.Time) (int64, error) {
+	filter := bson.M{
+		"status":                 bson.M{"$in": Domain.ReviewStatuses},
+		"created_at":             bson.M{"$lt": submittedBefore},
+		"review_sla_breached_at": bson.M{"$exists": false},
+	}
+	result, err := r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"review_sla_breached_at": at}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to flag review SLA breaches: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
+
 func (r *loanRepository) Delete(id primitive.ObjectID) error {
 	filter := bson.M{"id": id}
 	_, err := r.collection.DeleteOne(context.Background(), filter)
@@ -100,3 +575,21 @@ func (r *loanRepository) Delete(id primitive.ObjectID) error {
 	}
 	return nil
 }
+
+// CreateIndexes makes sure the indexes used by loan queries exist.
+func (r *loanRepository) CreateIndexes() error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "assignment.officer_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	}
+	// Every sortable field gets a compound index with the id tie-breaker used by cursor pagination
+	for _, field := range loanSortFields {
+		indexes = append(indexes, mongo.IndexModel{Keys: bson.D{{Key: field, Value: 1}, {Key: "id", Value: 1}}})
+	}
+	_, err := r.collection.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create loan indexes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoanRevisionRepository interface {
+	Save(revision *Domain.LoanRevision) error
+	FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanRevision, error)
+}
+
+type loanRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoanRevisionRepository(collection *mongo.Collection) LoanRevisionRepository {
+	return &loanRevisionRepository{
+		collection: collection,
+	}
+}
+
+func (r *loanRevisionRepository) Save(revision *Domain.LoanRevision) error {
+	_, err := r.collection.InsertOne(context.Background(), revision)
+	if err != nil {
+		return fmt.Errorf("failed to save loan revision: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the edits made to a loan, oldest first.
+func (r *loanRevisionRepository) FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan revisions: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var revisions []Domain.LoanRevision
+	if err = cursor.All(context.Background(), &revisions); err != nil {
+		return nil, fmt.Errorf("failed to parse loan revisions: %v", err)
+	}
+	return revisions, nil
+}
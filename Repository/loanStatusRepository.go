@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoanStatusRepository interface {
+	Save(status *Domain.LoanStatus) error
+	FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanStatus, error)
+}
+
+type loanStatusRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoanStatusRepository(collection *mongo.Collection) LoanStatusRepository {
+	return &loanStatusRepository{
+		collection: collection,
+	}
+}
+
+// Save appends a transition to the loan status history.
+func (r *loanStatusRepository) Save(status *Domain.LoanStatus) error {
+	_, err := r.collection.InsertOne(context.Background(), status)
+	if err != nil {
+		return fmt.Errorf("failed to save loan status history: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the status history of a loan in the order the transitions happened.
+func (r *loanStatusRepository) FindByLoanID(loanID primitive.ObjectID) ([]Domain.LoanStatus, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "changed_at", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan status history: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var history []Domain.LoanStatus
+	if err = cursor.All(context.Background(), &history); err != nil {
+		return nil, fmt.Errorf("failed to parse loan status history: %v", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrateMoneyFields converts amounts stored as plain numbers, as they were before
+// Domain.Money existed, into {minor_units, currency} documents. Paths may reach into
+// arrays of sub-documents, e.g. "installments.principal". Documents that are
+// already migrated do not match and are left alone, so it is safe to run on every start.
+func MigrateMoneyFields(collection *mongo.Collection, paths []string, currency string) (int, error) {
+	conditions := bson.A{}
+	for _, path := range paths {
+		conditions = append(conditions, bson.M{path: bson.M{"$type": bson.A{"double", "int", "long"}}})
+	}
+
+	cursor, err := collection.Find(context.Background(), bson.M{"$or": conditions})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find documents to migrate: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	migrated := 0
+	for cursor.Next(context.Background()) {
+		var document bson.M
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, fmt.Errorf("failed to decode document: %v", err)
+		}
+
+		for _, path := range paths {
+			convertMoneyPath(document, strings.Split(path, "."), currency)
+		}
+
+		_, err := collection.ReplaceOne(context.Background(), bson.M{"_id": document["_id"]}, document)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to migrate document: %v", err)
+		}
+		migrated++
+	}
+
+	if err := cursor.Err(); err != nil {
+		return migrated, err
+	}
+	return migrated, nil
+}
+
+func convertMoneyPath(value interface{}, path []string, currency string) interface{} {
+	if len(path) == 0 {
+		return toMoney(value, currency)
+	}
+
+	switch v := value.(type) {
+	case bson.M:
+		if field, ok := v[path[0]]; ok {
+			v[path[0]] = convertMoneyPath(field, path[1:], currency)
+		}
+	case bson.D:
+		for i := range v {
+			if v[i].Key == path[0] {
+				v[i].Value = convertMoneyPath(v[i].Value, path[1:], currency)
+			}
+		}
+	case bson.A:
+		for i := range v {
+			v[i] = convertMoneyPath(v[i], path, currency)
+		}
+	}
+	return value
+}
+
+func toMoney(value interface{}, currency string) interface{} {
+	var amount float64
+	switch v := value.(type) {
+	case float64:
+		amount = v
+	case int32:
+		amount = float64(v)
+	case int64:
+		amount = float64(v)
+	default:
+		return value
+	}
+
+	scale := math.Pow10(Domain.CurrencyExponent(currency))
+	return Domain.NewMoney(int64(math.Round(amount*scale)), currency)
+}
+
+// BackfillCurrency sets the top-level currency of documents saved before it
+// existed from the currency of one of their migrated amounts, e.g. "amount.currency".
+func BackfillCurrency(collection *mongo.Collection, sourcePath string) (int64, error) {
+	filter := bson.M{"currency": bson.M{"$exists": false}, sourcePath: bson.M{"$exists": true}}
+	update := bson.A{bson.M{"$set": bson.M{"currency": "$" + sourcePath}}}
+	result, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill currency: %v", err)
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository interface {
+	Save(message *Domain.OutboxMessage) error
+	ClaimNext(lockFor time.Duration) (*Domain.OutboxMessage, error)
+	MarkSent(id primitive.ObjectID) error
+	MarkFailed(id primitive.ObjectID, status string, nextAttemptAt time.Time, sendErr string) error
+	Requeue(id primitive.ObjectID) (*Domain.OutboxMessage, error)
+	FindByID(id primitive.ObjectID) (*Domain.OutboxMessage, error)
+	FindByStatus(status string, limit int) ([]Domain.OutboxMessage, error)
+	CreateIndexes() error
+}
+
+type outboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(collection *mongo.Collection) OutboxRepository {
+	return &outboxRepository{
+		collection: collection,
+	}
+}
+
+func (r *outboxRepository) Save(message *Domain.OutboxMessage) error {
+	_, err := r.collection.InsertOne(context.Background(), message)
+	if err != nil {
+		return fmt.Errorf("failed to save outbox message: %v", err)
+	}
+	return nil
+}
+
+// ClaimNext atomically takes the oldest message that is due for an attempt,
+// including messages whose worker stopped while sending them, and counts the
+// attempt. It returns nil when nothing is due.
+func (r *outboxRepository) ClaimNext(lockFor time.Duration) (*Domain.OutboxMessage, error) {
+	now := time.Now()
+	filter := bson.M{"$or": bson.A{
+		bson.M{"status": Domain.OutboxStatusPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"status": Domain.OutboxStatusSending, "locked_until": bson.M{"$lte": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": Domain.OutboxStatusSending, "locked_until": now.Add(lockFor)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var message Domain.OutboxMessage
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim outbox message: %v", err)
+	}
+	return &message, nil
+}
+
+func (r *outboxRepository) MarkSent(id primitive.ObjectID) error {
+	update := bson.M{
+		"$set":   bson.M{"status": Domain.OutboxStatusSent, "sent_at": time.Now()},
+		"$unset": bson.M{"locked_until": "", "last_error": ""},
+	}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %v", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt and either schedules the next one
+// (status pending) or dead-letters the message (status dead).
+func (r *outboxRepository) MarkFailed(id primitive.ObjectID, status string, nextAttemptAt time.Time, sendErr string) error {
+	update := bson.M{
+		"$set":   bson.M{"status": status, "next_attempt_at": nextAttemptAt, "last_error": sendErr},
+		"$unset": bson.M{"locked_until": ""},
+	}
+	_, err := r.collection.UpdateOne(context.Background(), bson.M{"id": id}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message: %v", err)
+	}
+	return nil
+}
+
+// Requeue gives a dead-lettered message a fresh set of attempts, starting now.
+// It returns nil if the message does not exist or is not dead.
+func (r *outboxRepository) Requeue(id primitive.ObjectID) (*Domain.OutboxMessage, error) {
+	filter := bson.M{"id": id, "status": Domain.OutboxStatusDead}
+	update := bson.M{"$set": bson.M{"status": Domain.OutboxStatusPending, "attempts": 0, "next_attempt_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var message Domain.OutboxMessage
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to requeue outbox message: %v", err)
+	}
+	return &message, nil
+}
+
+func (r *outboxRepository) FindByID(id primitive.ObjectID) (*Domain.OutboxMessage, error) {
+	var message Domain.OutboxMessage
+	err := r.collection.FindOne(context.Background(), bson.M{"id": id}).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, fmt.Errorf("outbox message not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get outbox message: %v", err)
+	}
+	return &message, nil
+}
+
+// FindByStatus retrieves the most recent messages, optionally with one status.
+func (r *outboxRepository) FindByStatus(status string, limit int) ([]Domain.OutboxMessage, error) {
+	query := bson.M{}
+	if status != "" {
+		query["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox messages: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	messages := []Domain.OutboxMessage{}
+	if err = cursor.All(context.Background(), &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse outbox messages: %v", err)
+	}
+	return messages, nil
+}
+
+// CreateIndexes keeps claiming the next due message and the admin listing cheap.
+func (r *outboxRepository) CreateIndexes() error {
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	}
+	_, err := r.collection.Indexes().CreateMany(context.Background(), indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox indexes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PaymentRepository interface {
+	Save(payment *Domain.Payment) error
+	FindByLoanID(loanID primitive.ObjectID) ([]Domain.Payment, error)
+}
+
+type paymentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPaymentRepository(collection *mongo.Collection) PaymentRepository {
+	return &paymentRepository{
+		collection: collection,
+	}
+}
+
+func (r *paymentRepository) Save(payment *Domain.Payment) error {
+	_, err := r.collection.InsertOne(context.Background(), payment)
+	if err != nil {
+		return fmt.Errorf("failed to save payment: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the payments of a loan, oldest first.
+func (r *paymentRepository) FindByLoanID(loanID primitive.ObjectID) ([]Domain.Payment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "paid_at", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var payments []Domain.Payment
+	if err = cursor.All(context.Background(), &payments); err != nil {
+		return nil, fmt.Errorf("failed to parse payments: %v", err)
+	}
+	return payments, nil
+}
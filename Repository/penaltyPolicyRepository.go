@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PenaltyPolicyRepository interface {
+	Save(policy *Domain.PenaltyPolicy) error
+	GetAllPolicies() ([]Domain.PenaltyPolicy, error)
+	Delete(currency string) (bool, error)
+}
+
+type penaltyPolicyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPenaltyPolicyRepository(collection *mongo.Collection) PenaltyPolicyRepository {
+	return &penaltyPolicyRepository{
+		collection: collection,
+	}
+}
+
+// Save stores the policy of a currency, replacing the previous one.
+func (r *penaltyPolicyRepository) Save(policy *Domain.PenaltyPolicy) error {
+	filter := bson.M{"currency": policy.Currency}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(context.Background(), filter, policy, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save penalty policy: %v", err)
+	}
+	return nil
+}
+
+func (r *penaltyPolicyRepository) GetAllPolicies() ([]Domain.PenaltyPolicy, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "currency", Value: 1}})
+	cursor, err := r.collection.Find(context.Background(), bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get penalty policies: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	policies := []Domain.PenaltyPolicy{}
+	if err = cursor.All(context.Background(), &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse penalty policies: %v", err)
+	}
+	return policies, nil
+}
+
+// Delete removes the policy of a currency and reports whether it existed.
+func (r *penaltyPolicyRepository) Delete(currency string) (bool, error) {
+	result, err := r.collection.DeleteOne(context.Background(), bson.M{"currency": currency})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete penalty policy: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
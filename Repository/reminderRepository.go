@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// reminderSettingsKey identifies the single settings document.
+const reminderSettingsKey = "default"
+
+type ReminderRepository interface {
+	GetSettings() (Domain.ReminderSettings, error)
+	SaveSettings(settings *Domain.ReminderSettings) error
+	ClaimDelivery(delivery *Domain.ReminderDelivery, maxAttempts int, staleAfter time.Duration) (bool, error)
+	UpdateDeliveryStatus(key string, status string, messageID primitive.ObjectID, deliveryErr string) error
+	FindByLoanID(loanID primitive.ObjectID) ([]Domain.ReminderDelivery, error)
+	CreateIndexes() error
+}
+
+type reminderRepository struct {
+	settingsCollection *mongo.Collection
+	deliveryCollection *mongo.Collection
+}
+
+func NewReminderRepository(settingsCollection *mongo.Collection, deliveryCollection *mongo.Collection) ReminderRepository {
+	return &reminderRepository{
+		settingsCollection: settingsCollection,
+		deliveryCollection: deliveryCollection,
+	}
+}
+
+// GetSettings returns the stored reminder settings, or the defaults if none were saved.
+func (r *reminderRepository) GetSettings() (Domain.ReminderSettings, error) {
+	var settings Domain.ReminderSettings
+	err := r.settingsCollection.FindOne(context.Background(), bson.M{"key": reminderSettingsKey}).Decode(&settings)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Domain.DefaultReminderSettings, nil
+		}
+		return Domain.ReminderSettings{}, fmt.Errorf("failed to get reminder settings: %v", err)
+	}
+	return settings, nil
+}
+
+func (r *reminderRepository) SaveSettings(settings *Domain.ReminderSettings) error {
+	filter := bson.M{"key": reminderSettingsKey}
+	update := bson.M{"$set": settings}
+	_, err := r.settingsCollection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save reminder settings: %v", err)
+	}
+	return nil
+}
+
+// ClaimDelivery records that a reminder is being sent and reports whether the
+// caller should send it. A reminder can only be claimed again after it failed,
+// or when it has been sending for longer than staleAfter because the replica
+// sending it stopped, and at most maxAttempts times; otherwise the upsert
+// collides with the unique key index and the claim is refused.
+func (r *reminderRepository) ClaimDelivery(delivery *Domain.ReminderDelivery, maxAttempts int, staleAfter time.Duration) (bool, error) {
+	filter := bson.M{
+		"key": delivery.Key,
+		"$or": bson.A{
+			bson.M{"status": Domain.ReminderStatusFailed},
+			bson.M{"status": Domain.ReminderStatusSending, "updated_at": bson.M{"$lt": time.Now().Add(-staleAfter)}},
+		},
+		"attempts": bson.M{"$lt": maxAttempts},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"loan_id":            delivery.LoanID,
+			"user_id":            delivery.UserID,
+			"installment_number": delivery.InstallmentNumber,
+			"due_date":           delivery.DueDate,
+			"kind":               delivery.Kind,
+			"days_before":        delivery.DaysBefore,
+			"email":              delivery.Email,
+			"status":             Domain.ReminderStatusSending,
+			"updated_at":         time.Now(),
+		},
+		"$unset":       bson.M{"error": ""},
+		"$inc":         bson.M{"attempts": 1},
+		"$setOnInsert": bson.M{"id": delivery.ID},
+	}
+
+	_, err := r.deliveryCollection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim reminder delivery: %v", err)
+	}
+	return true, nil
+}
+
+func (r *reminderRepository) UpdateDeliveryStatus(key string, status string, messageID primitive.ObjectID, deliveryErr string) error {
+	set := bson.M{"status": status, "updated_at": time.Now()}
+	if !messageID.IsZero() {
+		set["message_id"] = messageID
+	}
+	if deliveryErr != "" {
+		set["error"] = deliveryErr
+	}
+	_, err := r.deliveryCollection.UpdateOne(context.Background(), bson.M{"key": key}, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("failed to update reminder delivery: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the reminder deliveries of a loan by installment.
+func (r *reminderRepository) FindByLoanID(loanID primitive.ObjectID) ([]Domain.ReminderDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "installment_number", Value: 1}, {Key: "updated_at", Value: 1}})
+	cursor, err := r.deliveryCollection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminder deliveries: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	deliveries := []Domain.ReminderDelivery{}
+	if err = cursor.All(context.Background(), &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to parse reminder deliveries: %v", err)
+	}
+	return deliveries, nil
+}
+
+// CreateIndexes makes sure each reminder has a single delivery record.
+func (r *reminderRepository) CreateIndexes() error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := r.deliveryCollection.Indexes().CreateOne(context.Background(), index); err != nil {
+		return fmt.Errorf("failed to create reminder delivery indexes: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Keys of the documents stored in the review settings collection.
+const (
+	reviewSettingsKey   = "default"
+	roundRobinCursorKey = "round_robin"
+)
+
+type ReviewRepository interface {
+	GetSettings() (Domain.ReviewSettings, error)
+	SaveSettings(settings *Domain.ReviewSettings) error
+	NextRoundRobin() (int64, error)
+	SaveAssignment(change *Domain.LoanAssignmentChange) error
+	FindAssignmentsByLoanID(loanID primitive.ObjectID) ([]Domain.LoanAssignmentChange, error)
+}
+
+type reviewRepository struct {
+	settingsCollection   *mongo.Collection
+	assignmentCollection *mongo.Collection
+}
+
+func NewReviewRepository(settingsCollection *mongo.Collection, assignmentCollection *mongo.Collection) ReviewRepository {
+	return &reviewRepository{
+		settingsCollection:   settingsCollection,
+		assignmentCollection: assignmentCollection,
+	}
+}
+
+// GetSettings returns the stored review settings, or the defaults if none were saved.
+func (r *reviewRepository) GetSettings() (Domain.ReviewSettings, error) {
+	var settings Domain.ReviewSettings
+	err := r.settingsCollection.FindOne(context.Background(), bson.M{"key": reviewSettingsKey}).Decode(&settings)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Domain.DefaultReviewSettings, nil
+		}
+		return Domain.ReviewSettings{}, fmt.Errorf("failed to get review settings: %v", err)
+	}
+	return settings, nil
+}
+
+func (r *reviewRepository) SaveSettings(settings *Domain.ReviewSettings) error {
+	filter := bson.M{"key": reviewSettingsKey}
+	update := bson.M{"$set": settings}
+	_, err := r.settingsCollection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save review settings: %v", err)
+	}
+	return nil
+}
+
+// NextRoundRobin atomically advances the shared round-robin cursor and returns
+// its new value, so that replicas assigning at the same time pick different officers.
+func (r *reviewRepository) NextRoundRobin() (int64, error) {
+	filter := bson.M{"key": roundRobinCursorKey}
+	update := bson.M{"$inc": bson.M{"counter": int64(1)}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var cursor struct {
+		Counter int64 `bson:"counter"`
+	}
+	err := r.settingsCollection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance round-robin cursor: %v", err)
+	}
+	return cursor.Counter, nil
+}
+
+func (r *reviewRepository) SaveAssignment(change *Domain.LoanAssignmentChange) error {
+	_, err := r.assignmentCollection.InsertOne(context.Background(), change)
+	if err != nil {
+		return fmt.Errorf("failed to save loan assignment: %v", err)
+	}
+	return nil
+}
+
+// FindAssignmentsByLoanID retrieves the assignments of a loan, oldest first.
+func (r *reviewRepository) FindAssignmentsByLoanID(loanID primitive.ObjectID) ([]Domain.LoanAssignmentChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "assigned_at", Value: 1}})
+	cursor, err := r.assignmentCollection.Find(context.Background(), bson.M{"loan_id": loanID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loan assignments: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	changes := []Domain.LoanAssignmentChange{}
+	if err = cursor.All(context.Background(), &changes); err != nil {
+		return nil, fmt.Errorf("failed to parse loan assignments: %v", err)
+	}
+	return changes, nil
+}
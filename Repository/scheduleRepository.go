@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"Loan_Tracker/Domain"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ScheduleRepository interface {
+	Save(schedule *Domain.Schedule) error
+	FindByLoanID(loanID primitive.ObjectID) (Domain.Schedule, error)
+	FindByLoanIDs(loanIDs []primitive.ObjectID) ([]Domain.Schedule, error)
+}
+
+type scheduleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewScheduleRepository(collection *mongo.Collection) ScheduleRepository {
+	return &scheduleRepository{
+		collection: collection,
+	}
+}
+
+// Save stores the schedule for a loan, replacing any schedule generated earlier.
+func (r *scheduleRepository) Save(schedule *Domain.Schedule) error {
+	filter := bson.M{"loan_id": schedule.LoanID}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.collection.ReplaceOne(context.Background(), filter, schedule, opts)
+	if err != nil {
+		return fmt.Errorf("failed to save schedule: %v", err)
+	}
+	return nil
+}
+
+// FindByLoanID retrieves the installment schedule of a loan.
+func (r *scheduleRepository) FindByLoanID(loanID primitive.ObjectID) (Domain.Schedule, error) {
+	var schedule Domain.Schedule
+	err := r.collection.FindOne(context.Background(), bson.M{"loan_id": loanID}).Decode(&schedule)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return Domain.Schedule{}, fmt.Errorf("schedule not found: %v", err)
+		}
+		return Domain.Schedule{}, fmt.Errorf("failed to find schedule: %v", err)
+	}
+	return schedule, nil
+}
+
+// FindByLoanIDs retrieves the schedules of several loans; loans without a schedule are skipped.
+func (r *scheduleRepository) FindByLoanIDs(loanIDs []primitive.ObjectID) ([]Domain.Schedule, error) {
+	cursor, err := r.collection.Find(context.Background(), bson.M{"loan_id": bson.M{"$in": loanIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedules: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var schedules []Domain.Schedule
+	if err = cursor.All(context.Background(), &schedules); err != nil {
+		return nil, fmt.Errorf("failed to parse schedules: %v", err)
+	}
+	return schedules, nil
+}
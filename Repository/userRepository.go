@@ -9,20 +9,27 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository interface {
 	Save(user *Domain.User) error
 	FindByID(id string) (Domain.User, error)
+	FindByIDs(ids []primitive.ObjectID) ([]Domain.User, error)
 	FindByEmail(email string) (Domain.User, error)
 	FindByUsername(username string) (Domain.User, error)
 	Update(username string, UpdatedUser bson.M) error
 	Delete(userID string) error
-	IsDbEmpty() (bool, error)
+	HasRole(role string, excludeID primitive.ObjectID) (bool, error)
+	UpdateRoles(id primitive.ObjectID, roles []string) error
+	MigrateRoles() (int64, error)
 	InsertToken(username string, accessToke string, refreshToken string) error
 	ExpireToken(token string) error
+	ExpireUserTokens(username string) error
+	DeleteExpiredTokens(before time.Time) (int64, error)
+	FindFailedVerifications(maxAttempts int) ([]Domain.User, error)
 	ShowUser(id string) (Domain.User, error)
-	GetAllUsers() ([]Domain.User, error)
+	GetAllUsers(role string) ([]Domain.User, error)
 }
 
 type userRepository struct {
@@ -34,9 +41,13 @@ func NewUserRepository(collection *mongo.Collection, tokenCollection *mongo.Coll
 	return &userRepository{collection: collection, tokenCollection: tokenCollection}
 }
 
-func (ur *userRepository) GetAllUsers() ([]Domain.User, error) {
+// GetAllUsers retrieves every user, or only those holding the given role.
+func (ur *userRepository) GetAllUsers(role string) ([]Domain.User, error) {
 	var users []Domain.User
-	filter := bson.M{"role": "user"}
+	filter := bson.M{}
+	if role != "" {
+		filter["roles"] = role
+	}
 	cursor, err := ur.collection.Find(context.Background(), filter)
 	if err != nil {
 		return nil, err
@@ -69,6 +80,21 @@ func (ur *userRepository) FindByID(id string) (Domain.User, error) {
 	return user, err
 }
 
+// FindByIDs retrieves the users with the given IDs; IDs without a user are skipped.
+func (ur *userRepository) FindByIDs(ids []primitive.ObjectID) ([]Domain.User, error) {
+	cursor, err := ur.collection.Find(context.Background(), bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	var users []Domain.User
+	if err = cursor.All(context.Background(), &users); err != nil {
+		return nil, fmt.Errorf("failed to parse users: %v", err)
+	}
+	return users, nil
+}
+
 func (ur *userRepository) FindByEmail(email string) (Domain.User, error) {
 	var user Domain.User
 	err := ur.collection.FindOne(context.Background(), bson.M{"email": email}).Decode(&user)
@@ -91,9 +117,46 @@ func (ur *userRepository) Delete(userID string) error {
 	return err
 }
 
-func (ur *userRepository) IsDbEmpty() (bool, error) {
-	count, err := ur.collection.CountDocuments(context.Background(), bson.M{})
-	return count == 0, err
+// HasRole reports whether any user other than excludeID holds the role.
+func (ur *userRepository) HasRole(role string, excludeID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"roles": role, "id": bson.M{"$ne": excludeID}}
+	count, err := ur.collection.CountDocuments(context.Background(), filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to count users with role %s: %v", role, err)
+	}
+	return count > 0, nil
+}
+
+func (ur *userRepository) UpdateRoles(id primitive.ObjectID, roles []string) error {
+	result, err := ur.collection.UpdateOne(context.Background(), bson.M{"id": id}, bson.M{"$set": bson.M{"roles": roles}})
+	if err != nil {
+		return fmt.Errorf("failed to update roles: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found: %w", mongo.ErrNoDocuments)
+	}
+	return nil
+}
+
+// MigrateRoles gives users saved with the single role field, as they were
+// before role-based access control, the equivalent roles: "admin" keeps admin
+// and "user" becomes borrower. Migrated users no longer match, so it is safe
+// to run on every start.
+func (ur *userRepository) MigrateRoles() (int64, error) {
+	var migrated int64
+	for legacyRole, roles := range map[string][]string{
+		"admin": {Domain.RoleBorrower, Domain.RoleAdmin},
+		"user":  {Domain.RoleBorrower},
+	} {
+		filter := bson.M{"role": legacyRole, "roles": bson.M{"$exists": false}}
+		update := bson.M{"$set": bson.M{"roles": roles}, "$unset": bson.M{"role": ""}}
+		result, err := ur.collection.UpdateMany(context.Background(), filter, update)
+		if err != nil {
+			return migrated, fmt.Errorf("failed to migrate user roles: %v", err)
+		}
+		migrated += result.ModifiedCount
+	}
+	return migrated, nil
 }
 
 func (ur *userRepository) InsertToken(username string, accessToke string, refreshToken string) error {
@@ -129,6 +192,47 @@ func (ur *userRepository) ExpireToken(token string) error {
 	return nil
 }
 
+// ExpireUserTokens ends every session of the user, e.g. after their roles changed.
+func (ur *userRepository) ExpireUserTokens(username string) error {
+	now := time.Now()
+	filter := bson.M{"username": username, "expires_at": bson.M{"$gt": now}}
+	_, err := ur.tokenCollection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"expires_at": now}})
+	if err != nil {
+		return fmt.Errorf("failed to expire tokens: %v", err)
+	}
+	return nil
+}
+
+// DeleteExpiredTokens removes the tokens that expired before the given time.
+func (ur *userRepository) DeleteExpiredTokens(before time.Time) (int64, error) {
+	result, err := ur.tokenCollection.DeleteMany(context.Background(), bson.M{"expires_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired tokens: %v", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// FindFailedVerifications retrieves the inactive users whose verification
+// email could not be sent and has been attempted fewer than maxAttempts times.
+func (ur *userRepository) FindFailedVerifications(maxAttempts int) ([]Domain.User, error) {
+	filter := bson.M{
+		"is_active":                    false,
+		"verification_email_failed_at": bson.M{"$ne": nil},
+		"verification_email_attempts":  bson.M{"$lt": maxAttempts},
+	}
+	cursor, err := ur.collection.Find(context.Background(), filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unverified users: %v", err)
+	}
+	defer cursor.Close(context.Background())
+
+	users := []Domain.User{}
+	if err = cursor.All(context.Background(), &users); err != nil {
+		return nil, fmt.Errorf("failed to parse unverified users: %v", err)
+	}
+	return users, nil
+}
+
 func (ur *userRepository) ShowUser(id string) (Domain.User, error) {
 	var user Domain.User
 	filter := bson.M{"id": id}
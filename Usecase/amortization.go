@@ -0,0 +1,139 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	"errors"
+	"math"
+	"time"
+)
+
+// GenerateInstallments builds the repayment plan for a loan of the given principal.
+// The first installment falls due one month after start; amounts are rounded to
+// the currency's minor unit and the final installment absorbs any rounding difference.
+func GenerateInstallments(principal Domain.Money, annualRate float64, term int, method string, start time.Time) ([]Domain.Installment, error) {
+	if !principal.IsPositive() {
+		return nil, errors.New("principal must be greater than zero")
+	}
+	if term <= 0 {
+		return nil, errors.New("term must be greater than zero")
+	}
+	if annualRate < 0 {
+		return nil, errors.New("interest rate must not be negative")
+	}
+
+	switch method {
+	case Domain.InterestMethodReducingBalance:
+		return reducingBalanceInstallments(principal, annualRate, term, start)
+	case Domain.InterestMethodFlat:
+		return flatInstallments(principal, annualRate, term, start)
+	default:
+		return nil, errors.New("invalid interest method")
+	}
+}
+
+// reducingBalanceInstallments charges interest on the outstanding balance with a level monthly payment.
+func reducingBalanceInstallments(principal Domain.Money, annualRate float64, term int, start time.Time) ([]Domain.Installment, error) {
+	monthlyRate := annualRate / 100 / 12
+
+	payment, err := principal.Div(term)
+	if err != nil {
+		return nil, err
+	}
+	if monthlyRate > 0 {
+		payment = principal.MulRate(monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(term))))
+	}
+
+	installments := make([]Domain.Installment, 0, term)
+	balance := principal
+	for i := 1; i <= term; i++ {
+		interest := balance.MulRate(monthlyRate)
+		principalPart, err := payment.Sub(interest)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := principalPart.Cmp(balance)
+		if err != nil {
+			return nil, err
+		}
+		if i == term || cmp > 0 {
+			principalPart = balance
+		}
+		if balance, err = balance.Sub(principalPart); err != nil {
+			return nil, err
+		}
+
+		installment, err := newInstallment(i, addMonths(start, i), principalPart, interest, balance)
+		if err != nil {
+			return nil, err
+		}
+		installments = append(installments, installment)
+	}
+	return installments, nil
+}
+
+// flatInstallments charges interest on the original principal for the whole term.
+func flatInstallments(principal Domain.Money, annualRate float64, term int, start time.Time) ([]Domain.Installment, error) {
+	totalInterest := principal.MulRate(annualRate / 100 * float64(term) / 12)
+	principalPart, err := principal.Div(term)
+	if err != nil {
+		return nil, err
+	}
+	interest, err := totalInterest.Div(term)
+	if err != nil {
+		return nil, err
+	}
+
+	installments := make([]Domain.Installment, 0, term)
+	balance := principal
+	interestLeft := totalInterest
+	for i := 1; i <= term; i++ {
+		p, in := principalPart, interest
+		if i == term {
+			p, in = balance, interestLeft
+		}
+		if balance, err = balance.Sub(p); err != nil {
+			return nil, err
+		}
+		if interestLeft, err = interestLeft.Sub(in); err != nil {
+			return nil, err
+		}
+
+		installment, err := newInstallment(i, addMonths(start, i), p, in, balance)
+		if err != nil {
+			return nil, err
+		}
+		installments = append(installments, installment)
+	}
+	return installments, nil
+}
+
+func newInstallment(number int, dueDate time.Time, principal Domain.Money, interest Domain.Money, balance Domain.Money) (Domain.Installment, error) {
+	total, err := principal.Add(interest)
+	if err != nil {
+		return Domain.Installment{}, err
+	}
+	zero := Domain.NewMoney(0, principal.Currency)
+	return Domain.Installment{
+		Number:           number,
+		DueDate:          dueDate,
+		Principal:        principal,
+		Interest:         interest,
+		Total:            total,
+		RemainingBalance: balance,
+		PrincipalPaid:    zero,
+		InterestPaid:     zero,
+		Status:           Domain.InstallmentStatusDue,
+	}, nil
+}
+
+// addMonths moves t forward by the given number of months, clamping to the last
+// day of the target month so a loan started on the 31st stays at month end.
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	target := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := target.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return target.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,158 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	"testing"
+	"time"
+)
+
+func TestGenerateInstallments(t *testing.T) {
+	start := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		principal    Domain.Money
+		rate         float64
+		term         int
+		method       string
+		wantRegular  Domain.Money // Total of every installment but the last
+		wantLast     Domain.Money // Total of the last installment
+		wantInterest Domain.Money
+	}{
+		{
+			name: "reducing balance", principal: Domain.NewMoney(100000, "USD"), rate: 12, term: 12,
+			method:      Domain.InterestMethodReducingBalance,
+			wantRegular: Domain.NewMoney(8885, "USD"), wantLast: Domain.NewMoney(8884, "USD"), wantInterest: Domain.NewMoney(6619, "USD"),
+		},
+		{
+			name: "reducing balance short term", principal: Domain.NewMoney(100000, "USD"), rate: 10, term: 3,
+			method:      Domain.InterestMethodReducingBalance,
+			wantRegular: Domain.NewMoney(33890, "USD"), wantLast: Domain.NewMoney(33891, "USD"), wantInterest: Domain.NewMoney(1671, "USD"),
+		},
+		{
+			name: "reducing balance at 0%", principal: Domain.NewMoney(100000, "USD"), rate: 0, term: 12,
+			method:      Domain.InterestMethodReducingBalance,
+			wantRegular: Domain.NewMoney(8333, "USD"), wantLast: Domain.NewMoney(8337, "USD"), wantInterest: Domain.NewMoney(0, "USD"),
+		},
+		{
+			name: "flat", principal: Domain.NewMoney(100000, "USD"), rate: 12, term: 12,
+			method:      Domain.InterestMethodFlat,
+			wantRegular: Domain.NewMoney(9333, "USD"), wantLast: Domain.NewMoney(9337, "USD"), wantInterest: Domain.NewMoney(12000, "USD"),
+		},
+		{
+			name: "flat at 0%", principal: Domain.NewMoney(100000, "USD"), rate: 0, term: 3,
+			method:      Domain.InterestMethodFlat,
+			wantRegular: Domain.NewMoney(33333, "USD"), wantLast: Domain.NewMoney(33334, "USD"), wantInterest: Domain.NewMoney(0, "USD"),
+		},
+		{
+			name: "single installment", principal: Domain.NewMoney(100000, "USD"), rate: 12, term: 1,
+			method:   Domain.InterestMethodReducingBalance,
+			wantLast: Domain.NewMoney(101000, "USD"), wantInterest: Domain.NewMoney(1000, "USD"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments, err := GenerateInstallments(tt.principal, tt.rate, tt.term, tt.method, start)
+			if err != nil {
+				t.Fatalf("GenerateInstallments returned error: %v", err)
+			}
+			if len(installments) != tt.term {
+				t.Fatalf("got %d installments, want %d", len(installments), tt.term)
+			}
+
+			principal := Domain.NewMoney(0, "USD")
+			interest := Domain.NewMoney(0, "USD")
+			total := Domain.NewMoney(0, "USD")
+			for i, installment := range installments {
+				if installment.Number != i+1 {
+					t.Errorf("installment %d has number %d", i+1, installment.Number)
+				}
+				if want := addMonths(start, i+1); !installment.DueDate.Equal(want) {
+					t.Errorf("installment %d is due %s, want %s", i+1, installment.DueDate, want)
+				}
+				if sum, _ := installment.Principal.Add(installment.Interest); installment.Total != sum {
+					t.Errorf("installment %d total %s is not principal plus interest %s", i+1, installment.Total, sum)
+				}
+				want := tt.wantRegular
+				if i == len(installments)-1 {
+					want = tt.wantLast
+				}
+				if installment.Total != want {
+					t.Errorf("installment %d total = %s, want %s", i+1, installment.Total, want)
+				}
+				if err := accumulate(&principal, installment.Principal); err != nil {
+					t.Fatal(err)
+				}
+				if err := accumulate(&interest, installment.Interest); err != nil {
+					t.Fatal(err)
+				}
+				if err := accumulate(&total, installment.Total); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if principal != tt.principal {
+				t.Errorf("principal parts sum to %s, want %s", principal, tt.principal)
+			}
+			if interest != tt.wantInterest {
+				t.Errorf("interest sums to %s, want %s", interest, tt.wantInterest)
+			}
+			if want, _ := tt.principal.Add(tt.wantInterest); total != want {
+				t.Errorf("installments sum to %s, want %s", total, want)
+			}
+			if last := installments[len(installments)-1]; !last.RemainingBalance.IsZero() {
+				t.Errorf("remaining balance after the last installment = %s, want 0", last.RemainingBalance)
+			}
+		})
+	}
+}
+
+func TestGenerateInstallmentsRejectsInvalidTerms(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		principal Domain.Money
+		rate      float64
+		term      int
+		method    string
+	}{
+		{name: "zero principal", principal: Domain.NewMoney(0, "USD"), rate: 12, term: 12, method: Domain.InterestMethodFlat},
+		{name: "negative principal", principal: Domain.NewMoney(-100, "USD"), rate: 12, term: 12, method: Domain.InterestMethodFlat},
+		{name: "zero term", principal: Domain.NewMoney(100000, "USD"), rate: 12, term: 0, method: Domain.InterestMethodFlat},
+		{name: "negative rate", principal: Domain.NewMoney(100000, "USD"), rate: -1, term: 12, method: Domain.InterestMethodFlat},
+		{name: "unknown method", principal: Domain.NewMoney(100000, "USD"), rate: 12, term: 12, method: "compound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateInstallments(tt.principal, tt.rate, tt.term, tt.method, start); err == nil {
+				t.Errorf("GenerateInstallments returned no error")
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		start  time.Time
+		months int
+		want   time.Time
+	}{
+		{time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC), 1, time.Date(2024, 2, 15, 9, 30, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 1, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), 1, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 2, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC), 1, time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 12, 15, 0, 0, 0, 0, time.UTC), 1, time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), 12, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), 0, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := addMonths(tt.start, tt.months); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.start.Format("2006-01-02"), tt.months, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrSameApprover           = errors.New("the final approval must come from a different user than the recommendation")
+	ErrApprovalAuthority      = errors.New("you are not permitted to give the final approval on this loan")
+	ErrApprovalLimit          = errors.New("the loan amount is above your approval limit and the loan is already escalated")
+	ErrApprovalLimitNotFound  = errors.New("approval limit not found")
+	ErrApprovalPolicyNotFound = errors.New("approval policy not found")
+)
+
+type ApprovalUsecase interface {
+	SetPolicy(input Domain.ApprovalPolicyInput) (*Domain.ApprovalPolicy, error)
+	GetPolicies() ([]Domain.ApprovalPolicy, error)
+	DeletePolicy(currency string, deletedBy primitive.ObjectID) error
+	SetLimit(input Domain.ApprovalLimitInput) (*Domain.ApprovalLimit, error)
+	GetLimits() ([]Domain.ApprovalLimit, error)
+	DeleteLimit(id string, deletedBy primitive.ObjectID) error
+	ViewApprovals(loanID string) ([]Domain.LoanApprovalStep, error)
+	ViewEscalations(requester Domain.Requester) ([]Domain.Loan, error)
+}
+
+type approvalUsecase struct {
+	approvalRepo repository.ApprovalRepository
+	loanRepo     repository.LoanRepository
+	userRepo     repository.UserRepository
+	logRepo      repository.LogRepository
+	approvals    *loanApprovals
+}
+
+func NewApprovalUsecase(approvalRepo repository.ApprovalRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository, logRepo repository.LogRepository) ApprovalUsecase {
+	return &approvalUsecase{
+		approvalRepo: approvalRepo,
+		loanRepo:     loanRepo,
+		userRepo:     userRepo,
+		logRepo:      logRepo,
+		approvals:    newLoanApprovals(approvalRepo, loanRepo, logRepo),
+	}
+}
+
+// SetPolicy creates or replaces the approval policy of a currency. It applies
+// to every decision taken from now on, including on loans already under review.
+func (a *approvalUsecase) SetPolicy(input Domain.ApprovalPolicyInput) (*Domain.ApprovalPolicy, error) {
+	if !Domain.IsValidCurrency(input.Currency) {
+		return nil, errors.New("invalid currency")
+	}
+	threshold, err := input.DualControlAbove.WithCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if threshold.IsNegative() {
+		return nil, errors.New("dual_control_above cannot be negative")
+	}
+
+	policy := &Domain.ApprovalPolicy{
+		ID:               primitive.NewObjectID(),
+		Currency:         input.Currency,
+		DualControlAbove: threshold,
+		UpdatedBy:        input.UpdatedBy,
+		UpdatedAt:        time.Now(),
+	}
+	if err := a.approvalRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Approval Policy update",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("approval policy for %s set: dual control above %s", input.Currency, threshold),
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Approval Policy update: %v", err)
+	}
+
+	return policy, nil
+}
+
+func (a *approvalUsecase) GetPolicies() ([]Domain.ApprovalPolicy, error) {
+	return a.approvalRepo.GetAllPolicies()
+}
+
+func (a *approvalUsecase) DeletePolicy(currency string, deletedBy primitive.ObjectID) error {
+	deleted, err := a.approvalRepo.DeletePolicy(currency)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrApprovalPolicyNotFound
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Approval Policy update",
+		Timestamp: time.Now(),
+		UserID:    deletedBy.Hex(),
+		Message:   fmt.Sprintf("approval policy for %s deleted", currency),
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return fmt.Errorf("failed to log Approval Policy update: %v", err)
+	}
+	return nil
+}
+
+// SetLimit creates or replaces the approval limit of a role or of one user in a currency.
+func (a *approvalUsecase) SetLimit(input Domain.ApprovalLimitInput) (*Domain.ApprovalLimit, error) {
+	if !Domain.IsValidCurrency(input.Currency) {
+		return nil, errors.New("invalid currency")
+	}
+	maxAmount, err := input.MaxAmount.WithCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if maxAmount.IsNegative() {
+		return nil, errors.New("max_amount cannot be negative")
+	}
+
+	limit := &Domain.ApprovalLimit{
+		ID:        primitive.NewObjectID(),
+		Currency:  input.Currency,
+		MaxAmount: maxAmount,
+		UpdatedBy: input.UpdatedBy,
+		UpdatedAt: time.Now(),
+	}
+	holder := input.Role
+	switch {
+	case input.Role != "" && input.UserID != "":
+		return nil, errors.New("set either role or user_id, not both")
+	case input.Role != "":
+		if !Domain.HasPermission([]string{input.Role}, Domain.PermissionLoansApprove) {
+			return nil, fmt.Errorf("%w: %s cannot approve loans", ErrInvalidRole, input.Role)
+		}
+		limit.Role = input.Role
+	case input.UserID != "":
+		userID, err := primitive.ObjectIDFromHex(input.UserID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		users, err := a.userRepo.FindByIDs([]primitive.ObjectID{userID})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, ErrUserNotFound
+		}
+		limit.UserID = userID
+		holder = users[0].Username
+	default:
+		return nil, errors.New("role or user_id is required")
+	}
+
+	if err := a.approvalRepo.SaveLimit(limit); err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Approval Limit update",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("approval limit of %s set to %s", holder, maxAmount),
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Approval Limit update: %v", err)
+	}
+
+	return limit, nil
+}
+
+func (a *approvalUsecase) GetLimits() ([]Domain.ApprovalLimit, error) {
+	return a.approvalRepo.GetAllLimits()
+}
+
+func (a *approvalUsecase) DeleteLimit(id string, deletedBy primitive.ObjectID) error {
+	limitID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrApprovalLimitNotFound
+	}
+	deleted, err := a.approvalRepo.DeleteLimit(limitID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrApprovalLimitNotFound
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Approval Limit update",
+		Timestamp: time.Now(),
+		UserID:    deletedBy.Hex(),
+		Message:   fmt.Sprintf("approval limit %s deleted", id),
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return fmt.Errorf("failed to log Approval Limit update: %v", err)
+	}
+	return nil
+}
+
+// ViewEscalations lists the escalated loans the requester has the authority to
+// approve: within their approval limit, and neither their own loan nor one
+// they escalated themselves.
+func (a *approvalUsecase) ViewEscalations(requester Domain.Requester) ([]Domain.Loan, error) {
+	loans, err := a.loanRepo.FindEscalated()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := map[string]*Domain.Money{}
+	queue := []Domain.Loan{}
+	for _, loan := range loans {
+		if loan.UserID == requester.UserID || loan.Escalation.EscalatedBy == requester.UserID {
+			continue
+		}
+		limit, ok := limits[loan.Currency]
+		if !ok {
+			limit, err = a.approvals.LimitFor(requester.UserID, requester.Roles, loan.Currency)
+			if err != nil {
+				return nil, err
+			}
+			limits[loan.Currency] = limit
+		}
+		if limit == nil {
+			queue = append(queue, loan)
+			continue
+		}
+		cmp, err := loan.Amount.Cmp(*limit)
+		if err != nil {
+			return nil, err
+		}
+		if cmp <= 0 {
+			queue = append(queue, loan)
+		}
+	}
+	return queue, nil
+}
+
+// ViewApprovals lists the recommendations and decisions taken on a loan.
+func (a *approvalUsecase) ViewApprovals(loanID string) ([]Domain.LoanApprovalStep, error) {
+	loan, err := findLoan(a.loanRepo, loanID)
+	if err != nil {
+		return nil, err
+	}
+	return a.approvalRepo.FindStepsByLoanID(loan.ID)
+}
+
+// loanApprovals applies the dual control policies and approval limits to
+// staff decisions on behalf of the loan use case.
+type loanApprovals struct {
+	approvalRepo repository.ApprovalRepository
+	loanRepo     repository.LoanRepository
+	logRepo      repository.LogRepository
+}
+
+func newLoanApprovals(approvalRepo repository.ApprovalRepository, loanRepo repository.LoanRepository, logRepo repository.LogRepository) *loanApprovals {
+	return &loanApprovals{
+		approvalRepo: approvalRepo,
+		loanRepo:     loanRepo,
+		logRepo:      logRepo,
+	}
+}
+
+// LimitFor returns the highest amount the user may approve in a currency, or
+// nil if they may approve any amount. The user's own limit wins; otherwise
+// the highest limit among their roles that can approve loans applies, and a
+// role without a limit leaves the user unlimited.
+func (a *loanApprovals) LimitFor(userID primitive.ObjectID, roles []string, currency string) (*Domain.Money, error) {
+	limits, err := a.approvalRepo.FindLimits(userID, roles, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	roleLimits := map[string]Domain.Money{}
+	for _, limit := range limits {
+		if limit.UserID == userID {
+			return &limit.MaxAmount, nil
+		}
+		roleLimits[limit.Role] = limit.MaxAmount
+	}
+
+	var highest *Domain.Money
+	for _, role := range roles {
+		if !Domain.HasPermission([]string{role}, Domain.PermissionLoansApprove) {
+			continue
+		}
+		limit, ok := roleLimits[role]
+		if !ok {
+			return nil, nil
+		}
+		if highest == nil {
+			highest = &limit
+			continue
+		}
+		cmp, err := limit.Cmp(*highest)
+		if err != nil {
+			return nil, err
+		}
+		if cmp > 0 {
+			highest = &limit
+		}
+	}
+	return highest, nil
+}
+
+// Escalate records that the approver's limit is below the loan amount, so
+// that the loan waits in the escalation queue for someone with higher authority.
+func (a *loanApprovals) Escalate(loan *Domain.Loan, approverID primitive.ObjectID, limit Domain.Money, comment string) (*Domain.LoanApprovalStep, error) {
+	escalation := &Domain.LoanEscalation{
+		EscalatedBy:   approverID,
+		EscalatedAt:   time.Now(),
+		ApproverLimit: limit,
+		Comment:       comment,
+	}
+	if err := a.loanRepo.Update(loan.ID, bson.M{"escalation": escalation, "updated_at": time.Now()}); err != nil {
+		return nil, err
+	}
+	loan.Escalation = escalation
+
+	step, err := a.Record(loan, Domain.ApprovalStepEscalated, approverID, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Escalation",
+		Timestamp: time.Now(),
+		UserID:    approverID.Hex(),
+		Message:   fmt.Sprintf("loan %s for %s escalated: above the approval limit of %s", loan.ID.Hex(), loan.Amount, limit),
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Loan Escalation: %v", err)
+	}
+	return step, nil
+}
+
+// RequiresDualControl reports whether approving the loan takes two users.
+func (a *loanApprovals) RequiresDualControl(loan *Domain.Loan) (bool, error) {
+	policy, err := a.approvalRepo.FindPolicy(loan.Currency)
+	if err != nil || policy == nil {
+		return false, err
+	}
+	cmp, err := loan.Amount.Cmp(policy.DualControlAbove)
+	if err != nil {
+		return false, err
+	}
+	return cmp > 0, nil
+}
+
+// AuthorizeFinalApproval returns the recommendation of a loan under dual
+// control, or nil if there is none yet, after checking that the approver
+// may confirm it.
+func (a *loanApprovals) AuthorizeFinalApproval(loan *Domain.Loan, approverID primitive.ObjectID, roles []string) (*Domain.LoanApprovalStep, error) {
+	recommendation, err := a.approvalRepo.FindLatestStep(loan.ID, Domain.ApprovalStepRecommended)
+	if err != nil || recommendation == nil {
+		return nil, err
+	}
+	if recommendation.ActorID == approverID {
+		return nil, ErrSameApprover
+	}
+	if !Domain.HasPermission(roles, Domain.PermissionLoansFinalApprove) {
+		return nil, ErrApprovalAuthority
+	}
+	return recommendation, nil
+}
+
+// Record saves a step taken on the loan.
+func (a *loanApprovals) Record(loan *Domain.Loan, action string, actorID primitive.ObjectID, comment string) (*Domain.LoanApprovalStep, error) {
+	step := &Domain.LoanApprovalStep{
+		ID:        primitive.NewObjectID(),
+		LoanID:    loan.ID,
+		Action:    action,
+		ActorID:   actorID,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	}
+	if err := a.approvalRepo.SaveStep(step); err != nil {
+		return nil, err
+	}
+
+	if action == Domain.ApprovalStepRecommended {
+		log := &Domain.LogEntry{
+			ID:        primitive.NewObjectID(),
+			LogType:   "Loan Recommendation",
+			Timestamp: time.Now(),
+			UserID:    actorID.Hex(),
+			Message:   fmt.Sprintf("loan %s recommended for approval, awaiting a second approver", loan.ID.Hex()),
+		}
+		if err := a.logRepo.Save(log); err != nil {
+			return nil, fmt.Errorf("failed to log Loan Recommendation: %v", err)
+		}
+	}
+	return step, nil
+}
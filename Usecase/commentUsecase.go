@@ -0,0 +1,259 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrCommentNotFound   = errors.New("comment not found")
+	ErrNotCommentAuthor  = errors.New("only the author can edit this comment")
+	ErrInvalidVisibility = errors.New("invalid comment visibility")
+)
+
+const maxCommentLength = 5000
+
+type CommentUsecase interface {
+	AddComment(loanID string, input Domain.LoanCommentInput, requester Domain.Requester) (*Domain.LoanComment, error)
+	EditComment(loanID string, commentID string, input Domain.LoanCommentInput, requester Domain.Requester) (*Domain.LoanComment, error)
+	ViewComments(loanID string, requester Domain.Requester) ([]Domain.LoanComment, error)
+}
+
+type commentUsecase struct {
+	commentRepo repository.CommentRepository
+	loanRepo    repository.LoanRepository
+	userRepo    repository.UserRepository
+	logRepo     repository.LogRepository
+	outbox      OutboxUsecase
+	templates   EmailTemplateUsecase
+	policy      *loanAccessPolicy
+}
+
+func NewCommentUsecase(commentRepo repository.CommentRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository, logRepo repository.LogRepository, outbox OutboxUsecase, templates EmailTemplateUsecase) CommentUsecase {
+	return &commentUsecase{
+		commentRepo: commentRepo,
+		loanRepo:    loanRepo,
+		userRepo:    userRepo,
+		logRepo:     logRepo,
+		outbox:      outbox,
+		templates:   templates,
+		policy:      newLoanAccessPolicy(logRepo),
+	}
+}
+
+// AddComment posts to the thread of a loan. Staff write internal notes unless
+// they ask for a borrower-visible message; the borrower can only write
+// messages, and each one is emailed to the staff assigned to the loan.
+func (c *commentUsecase) AddComment(loanID string, input Domain.LoanCommentInput, requester Domain.Requester) (*Domain.LoanComment, error) {
+	loan, err := c.policy.FindVisibleLoan(c.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+	body, err := validateCommentBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	staff := c.policy.IsStaff(&loan, requester)
+	visibility := input.Visibility
+	if visibility == "" {
+		visibility = Domain.CommentVisibilityBorrower
+		if staff {
+			visibility = Domain.CommentVisibilityInternal
+		}
+	}
+	if !containsString(Domain.CommentVisibilities, visibility) || (!staff && visibility != Domain.CommentVisibilityBorrower) {
+		return nil, ErrInvalidVisibility
+	}
+
+	authors, err := c.userRepo.FindByIDs([]primitive.ObjectID{requester.UserID})
+	if err != nil {
+		return nil, err
+	}
+	if len(authors) == 0 {
+		return nil, ErrUserNotFound
+	}
+	author := authors[0]
+
+	comment := &Domain.LoanComment{
+		ID:         primitive.NewObjectID(),
+		LoanID:     loan.ID,
+		AuthorID:   author.ID,
+		AuthorName: author.Name,
+		FromStaff:  staff,
+		Visibility: visibility,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+	if err := c.commentRepo.Save(comment); err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Comment",
+		Timestamp: time.Now(),
+		UserID:    requester.UserID.Hex(),
+		Message:   fmt.Sprintf("%s comment %s added to loan %s", visibility, comment.ID.Hex(), loan.ID.Hex()),
+	}
+	if err := c.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Loan Comment: %v", err)
+	}
+
+	if !staff {
+		c.notifyStaff(&loan, comment)
+	}
+	return comment, nil
+}
+
+// EditComment replaces the body of a comment, keeping the previous one in its
+// edit history. Only the author can edit a comment, and its visibility is fixed.
+func (c *commentUsecase) EditComment(loanID string, commentID string, input Domain.LoanCommentInput, requester Domain.Requester) (*Domain.LoanComment, error) {
+	loan, err := c.policy.FindVisibleLoan(c.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+	comment, err := c.commentRepo.FindByID(loan.ID, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	if comment.Visibility == Domain.CommentVisibilityInternal && !c.policy.IsStaff(&loan, requester) {
+		return nil, ErrCommentNotFound
+	}
+	if comment.AuthorID != requester.UserID {
+		return nil, ErrNotCommentAuthor
+	}
+	if input.Visibility != "" && input.Visibility != comment.Visibility {
+		return nil, fmt.Errorf("%w: the visibility of a comment cannot be changed", ErrInvalidVisibility)
+	}
+
+	body, err := validateCommentBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if body == comment.Body {
+		return &comment, nil
+	}
+
+	previous := Domain.LoanCommentEdit{Body: comment.Body, EditedAt: time.Now()}
+	if err := c.commentRepo.UpdateBody(comment.ID, body, previous); err != nil {
+		return nil, err
+	}
+	comment.Body = body
+	comment.EditedAt = &previous.EditedAt
+	comment.Edits = append(comment.Edits, previous)
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Comment edit",
+		Timestamp: time.Now(),
+		UserID:    requester.UserID.Hex(),
+		Message:   fmt.Sprintf("comment %s on loan %s edited", comment.ID.Hex(), loan.ID.Hex()),
+	}
+	if err := c.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Loan Comment edit: %v", err)
+	}
+	return &comment, nil
+}
+
+// ViewComments lists the thread of a loan, oldest first. The borrower only
+// sees the borrower-visible messages.
+func (c *commentUsecase) ViewComments(loanID string, requester Domain.Requester) ([]Domain.LoanComment, error) {
+	loan, err := c.policy.FindVisibleLoan(c.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	visibilities := []string{Domain.CommentVisibilityBorrower}
+	if c.policy.IsStaff(&loan, requester) {
+		visibilities = Domain.CommentVisibilities
+	}
+	return c.commentRepo.FindByLoanID(loan.ID, visibilities)
+}
+
+// notifyStaff emails a borrower's message to the officer assigned to the loan
+// and its designated reviewers. The message is already posted, so failures
+// are written to the activity log instead of failing the request.
+func (c *commentUsecase) notifyStaff(loan *Domain.Loan, comment *Domain.LoanComment) {
+	var recipientIDs []primitive.ObjectID
+	if loan.Assignment != nil {
+		recipientIDs = append(recipientIDs, loan.Assignment.OfficerID)
+	}
+	for _, reviewerID := range loan.ReviewerIDs {
+		if !containsObjectID(recipientIDs, reviewerID) {
+			recipientIDs = append(recipientIDs, reviewerID)
+		}
+	}
+	if len(recipientIDs) == 0 {
+		return
+	}
+
+	staff, err := c.userRepo.FindByIDs(recipientIDs)
+	if err != nil {
+		c.logNotificationFailure(comment, err)
+		return
+	}
+	for _, user := range staff {
+		if user.ID == comment.AuthorID || user.Email == "" {
+			continue
+		}
+		message, err := c.templates.Render(Domain.EmailTemplateLoanMessage, user.Locale, map[string]interface{}{
+			"Name":     user.Name,
+			"LoanID":   loan.ID.Hex(),
+			"Borrower": comment.AuthorName,
+			"Message":  comment.Body,
+		})
+		if err == nil {
+			message.To = user.Email
+			_, err = c.outbox.Enqueue(message, Domain.EmailTemplateLoanMessage)
+		}
+		if err != nil {
+			c.logNotificationFailure(comment, err)
+		}
+	}
+}
+
+func (c *commentUsecase) logNotificationFailure(comment *Domain.LoanComment, err error) {
+	c.logRepo.Save(&Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Comment",
+		Timestamp: time.Now(),
+		UserID:    comment.AuthorID.Hex(),
+		Message:   fmt.Sprintf("staff could not be notified of comment %s on loan %s: %v", comment.ID.Hex(), comment.LoanID.Hex(), err),
+	})
+}
+
+func validateCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("comment body cannot be longer than %d characters", maxCommentLength)
+	}
+	return body, nil
+}
+
+func containsObjectID(values []primitive.ObjectID, value primitive.ObjectID) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,331 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// delinquencyStatuses are the statuses of loans that are repaying and can fall behind.
+// Only these loans are classified for delinquency and charged late fees.
+var delinquencyStatuses = []string{Domain.LoanStatusActive, Domain.LoanStatusDefaulted}
+
+// DelinquencyReport classifies the active loan book by days past due as of
+// today. Loan level rows are only included when includeLoans is set.
+func (r *reportUsecase) DelinquencyReport(includeLoans bool) (Domain.DelinquencyReport, error) {
+	asOf := startOfDay(time.Now())
+	totals := newDelinquencyTotals()
+
+	var loans []Domain.LoanDelinquency
+	err := r.classifyLoans(asOf, func(classification Domain.LoanDelinquency) error {
+		if err := totals.add(classification); err != nil {
+			return err
+		}
+		if includeLoans {
+			loans = append(loans, classification)
+		}
+		return nil
+	})
+	if err != nil {
+		return Domain.DelinquencyReport{}, err
+	}
+
+	report := totals.report(asOf)
+	report.Loans = loans
+	return report, nil
+}
+
+// ClassifyDelinquency stores today's days past due and bucket on every active
+// loan and keeps the day's totals as a snapshot. It is meant to run once a
+// day; running it again on the same day replaces that day's snapshot.
+func (r *reportUsecase) ClassifyDelinquency(runBy primitive.ObjectID) (Domain.DelinquencyReport, error) {
+	now := time.Now()
+	asOf := startOfDay(now)
+	totals := newDelinquencyTotals()
+
+	batch := make([]Domain.LoanDelinquency, 0, loanBatchSize)
+	err := r.classifyLoans(asOf, func(classification Domain.LoanDelinquency) error {
+		if err := totals.add(classification); err != nil {
+			return err
+		}
+		batch = append(batch, classification)
+		if len(batch) < loanBatchSize {
+			return nil
+		}
+		err := r.loanRepo.UpdateDelinquency(batch, now)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return Domain.DelinquencyReport{}, err
+	}
+	if err := r.loanRepo.UpdateDelinquency(batch, now); err != nil {
+		return Domain.DelinquencyReport{}, err
+	}
+
+	report := totals.report(asOf)
+	if err := r.delinquencyRepo.Save(&report); err != nil {
+		return Domain.DelinquencyReport{}, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Delinquency Classification",
+		Timestamp: time.Now(),
+		UserID:    runBy.Hex(),
+		Message:   fmt.Sprintf("loans classified by days past due as of %s", asOf.Format("2006-01-02")),
+	}
+	if err := r.logRepo.Save(log); err != nil {
+		return Domain.DelinquencyReport{}, fmt.Errorf("failed to log Delinquency Classification: %v", err)
+	}
+
+	return report, nil
+}
+
+// DelinquencySnapshot returns the totals stored by the classification of an earlier day.
+func (r *reportUsecase) DelinquencySnapshot(date time.Time) (Domain.DelinquencyReport, error) {
+	return r.delinquencyRepo.FindByDate(startOfDay(date))
+}
+
+// ExportDelinquency writes one row per active loan with its days past due and bucket.
+func (r *reportUsecase) ExportDelinquency(w infrastructure.RowWriter) error {
+	err := w.WriteRow([]string{
+		"loan_id", "user_id", "status", "currency", "days_past_due", "bucket",
+		"oldest_due_date", "overdue_amount", "outstanding_principal",
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.classifyLoans(startOfDay(time.Now()), func(classification Domain.LoanDelinquency) error {
+		oldestDueDate := ""
+		if classification.OldestDueDate != nil {
+			oldestDueDate = classification.OldestDueDate.Format("2006-01-02")
+		}
+		return w.WriteRow([]string{
+			classification.LoanID.Hex(),
+			classification.UserID.Hex(),
+			classification.Status,
+			classification.Currency,
+			strconv.Itoa(classification.DaysPastDue),
+			classification.Bucket,
+			oldestDueDate,
+			classification.OverdueAmount.Decimal(),
+			classification.OutstandingPrincipal.Decimal(),
+		})
+	})
+}
+
+// classifyLoans calls fn with the classification of every active and defaulted loan.
+func (r *reportUsecase) classifyLoans(asOf time.Time, fn func(classification Domain.LoanDelinquency) error) error {
+	return streamRepayingLoans(r.loanRepo, r.scheduleRepo, func(loan Domain.Loan, schedule Domain.Schedule) error {
+		classification, err := classifyLoan(loan, schedule, asOf)
+		if err != nil {
+			return err
+		}
+		return fn(classification)
+	})
+}
+
+// streamRepayingLoans streams the active and defaulted loans, looks up their
+// schedules in batches and calls fn with each loan and its schedule.
+func streamRepayingLoans(loanRepo repository.LoanRepository, scheduleRepo repository.ScheduleRepository, fn func(loan Domain.Loan, schedule Domain.Schedule) error) error {
+	batch := make([]Domain.Loan, 0, loanBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ids := make([]primitive.ObjectID, 0, len(batch))
+		for _, loan := range batch {
+			ids = append(ids, loan.ID)
+		}
+		schedules, err := scheduleRepo.FindByLoanIDs(ids)
+		if err != nil {
+			return err
+		}
+		byLoan := make(map[primitive.ObjectID]Domain.Schedule, len(schedules))
+		for _, schedule := range schedules {
+			byLoan[schedule.LoanID] = schedule
+		}
+
+		for _, loan := range batch {
+			if err := fn(loan, byLoan[loan.ID]); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, status := range delinquencyStatuses {
+		search := Domain.LoanSearch{Status: status, SortBy: "created_at", Order: "asc"}
+		err := loanRepo.StreamLoans(search, func(loan Domain.Loan) error {
+			batch = append(batch, loan)
+			if len(batch) < loanBatchSize {
+				return nil
+			}
+			return flush()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// classifyLoan works out how many days the oldest unpaid installment of a
+// loan has been overdue on the day starting at asOf. An installment becomes
+// overdue the day after its due date.
+func classifyLoan(loan Domain.Loan, schedule Domain.Schedule, asOf time.Time) (Domain.LoanDelinquency, error) {
+	classification := Domain.LoanDelinquency{
+		LoanID:               loan.ID,
+		UserID:               loan.UserID,
+		Status:               loan.Status,
+		Currency:             loan.Currency,
+		OverdueAmount:        Domain.NewMoney(0, loan.Currency),
+		OutstandingPrincipal: loan.OutstandingPrincipal,
+	}
+
+	for _, installment := range schedule.Installments {
+		dueDay := startOfDay(installment.DueDate)
+		if !dueDay.Before(asOf) {
+			break
+		}
+		unpaid, err := installment.Unpaid()
+		if err != nil {
+			return Domain.LoanDelinquency{}, err
+		}
+		if !unpaid.IsPositive() {
+			continue
+		}
+		if classification.OldestDueDate == nil {
+			dueDate := installment.DueDate
+			classification.OldestDueDate = &dueDate
+			classification.DaysPastDue = int(asOf.Sub(dueDay).Hours() / 24)
+		}
+		if err := accumulate(&classification.OverdueAmount, unpaid); err != nil {
+			return Domain.LoanDelinquency{}, err
+		}
+	}
+
+	classification.Bucket = delinquencyBucket(classification.DaysPastDue)
+	return classification, nil
+}
+
+func delinquencyBucket(daysPastDue int) string {
+	switch {
+	case daysPastDue <= 0:
+		return Domain.DelinquencyCurrent
+	case daysPastDue <= 30:
+		return Domain.Delinquency1To30
+	case daysPastDue <= 60:
+		return Domain.Delinquency31To60
+	case daysPastDue <= 90:
+		return Domain.Delinquency61To90
+	default:
+		return Domain.DelinquencyOver90
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// delinquencyTotals accumulates bucket totals and portfolio at risk per currency.
+type delinquencyTotals struct {
+	buckets map[string]map[string]*Domain.DelinquencyBucketTotal // bucket -> currency -> total
+	par     map[string]*Domain.PortfolioAtRisk                   // currency -> PAR
+}
+
+func newDelinquencyTotals() *delinquencyTotals {
+	return &delinquencyTotals{
+		buckets: map[string]map[string]*Domain.DelinquencyBucketTotal{},
+		par:     map[string]*Domain.PortfolioAtRisk{},
+	}
+}
+
+func (t *delinquencyTotals) add(classification Domain.LoanDelinquency) error {
+	currency := classification.Currency
+	zero := Domain.NewMoney(0, currency)
+
+	if t.buckets[classification.Bucket] == nil {
+		t.buckets[classification.Bucket] = map[string]*Domain.DelinquencyBucketTotal{}
+	}
+	bucket := t.buckets[classification.Bucket][currency]
+	if bucket == nil {
+		bucket = &Domain.DelinquencyBucketTotal{Bucket: classification.Bucket, Currency: currency, OutstandingPrincipal: zero, OverdueAmount: zero}
+		t.buckets[classification.Bucket][currency] = bucket
+	}
+	bucket.LoanCount++
+	if err := accumulate(&bucket.OutstandingPrincipal, classification.OutstandingPrincipal); err != nil {
+		return err
+	}
+	if err := accumulate(&bucket.OverdueAmount, classification.OverdueAmount); err != nil {
+		return err
+	}
+
+	par := t.par[currency]
+	if par == nil {
+		par = &Domain.PortfolioAtRisk{Currency: currency, OutstandingPrincipal: zero, AtRisk30: zero, AtRisk90: zero}
+		t.par[currency] = par
+	}
+	if err := accumulate(&par.OutstandingPrincipal, classification.OutstandingPrincipal); err != nil {
+		return err
+	}
+	if classification.DaysPastDue > 30 {
+		if err := accumulate(&par.AtRisk30, classification.OutstandingPrincipal); err != nil {
+			return err
+		}
+	}
+	if classification.DaysPastDue > 90 {
+		if err := accumulate(&par.AtRisk90, classification.OutstandingPrincipal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// report lists the buckets in aging order and the currencies alphabetically.
+func (t *delinquencyTotals) report(asOf time.Time) Domain.DelinquencyReport {
+	report := Domain.DelinquencyReport{
+		ID:          primitive.NewObjectID(),
+		AsOf:        asOf,
+		Buckets:     []Domain.DelinquencyBucketTotal{},
+		PAR:         []Domain.PortfolioAtRisk{},
+		GeneratedAt: time.Now(),
+	}
+
+	for _, name := range Domain.DelinquencyBuckets {
+		currencies := make([]string, 0, len(t.buckets[name]))
+		for currency := range t.buckets[name] {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+		for _, currency := range currencies {
+			report.Buckets = append(report.Buckets, *t.buckets[name][currency])
+		}
+	}
+
+	currencies := make([]string, 0, len(t.par))
+	for currency := range t.par {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		par := *t.par[currency]
+		if par.OutstandingPrincipal.IsPositive() {
+			par.PAR30 = float64(par.AtRisk30.MinorUnits) / float64(par.OutstandingPrincipal.MinorUnits)
+			par.PAR90 = float64(par.AtRisk90.MinorUnits) / float64(par.OutstandingPrincipal.MinorUnits)
+		}
+		report.PAR = append(report.PAR, par)
+	}
+
+	return report
+}
@@ -0,0 +1,246 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrPayoutsDisabled is returned for provider disbursements when no payout provider is configured.
+	ErrPayoutsDisabled = errors.New("no payout provider is configured; record the transfer manually")
+
+	// ErrAlreadyDisbursing is returned when the loan already has a pending or completed disbursement.
+	ErrAlreadyDisbursing = errors.New("loan already has a pending or completed disbursement")
+)
+
+// disbursementPendingTimeout is how long a disbursement may stay pending before
+// a retry treats the request that claimed it as lost and claims it again.
+const disbursementPendingTimeout = 15 * time.Minute
+
+type DisbursementUsecase interface {
+	DisburseLoan(loanID string, input Domain.DisbursementInput) (*Domain.Disbursement, error)
+	ViewDisbursement(loanID string) (Domain.Disbursement, error)
+}
+
+type disbursementUsecase struct {
+	disbursementRepo repository.DisbursementRepository
+	loanRepo         repository.LoanRepository
+	logRepo          repository.LogRepository
+	payoutProvider   infrastructure.PayoutProvider
+	lifecycle        *loanLifecycle
+	policy           *loanAccessPolicy
+}
+
+func NewDisbursementUsecase(disbursementRepo repository.DisbursementRepository, loanRepo repository.LoanRepository, scheduleRepo repository.ScheduleRepository, statusRepo repository.LoanStatusRepository, productRepo repository.LoanProductRepository, logRepo repository.LogRepository, payoutProvider infrastructure.PayoutProvider) DisbursementUsecase {
+	return &disbursementUsecase{
+		disbursementRepo: disbursementRepo,
+		loanRepo:         loanRepo,
+		logRepo:          logRepo,
+		payoutProvider:   payoutProvider,
+		lifecycle:        newLoanLifecycle(loanRepo, statusRepo, scheduleRepo, productRepo, logRepo),
+		policy:           newLoanAccessPolicy(logRepo),
+	}
+}
+
+// DisburseLoan pays out an approved loan, either through the payout provider or
+// by recording a transfer made outside the system, then rebuilds the repayment
+// schedule from the disbursement date and activates the loan. payoutProvider
+// is nil when provider payouts are disabled.
+//
+// The disbursement is claimed as pending before any money moves, so that a
+// concurrent request for the same loan is refused, and the provider gets the
+// loan ID as idempotency key so that a retry after a failure cannot pay twice.
+// Once the funds are out the disbursement is paid, and it is only completed
+// when the loan is active; a retry of a paid disbursement resumes the
+// activation with the stored reference instead of paying again.
+func (d *disbursementUsecase) DisburseLoan(loanID string, input Domain.DisbursementInput) (*Domain.Disbursement, error) {
+	id, err := primitive.ObjectIDFromHex(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := d.loanRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.policy.AuthorizeStaffAction(&loan, input.RecordedBy); err != nil {
+		return nil, err
+	}
+
+	existing, err := d.disbursementRepo.FindByLoanID(id)
+	if err != nil && !errors.Is(err, repository.ErrDisbursementNotFound) {
+		return nil, err
+	}
+	if err == nil && existing.Status == Domain.DisbursementStatusPaid {
+		if err := d.activate(&loan, &existing, input.RecordedBy); err != nil {
+			return nil, err
+		}
+		return &existing, nil
+	}
+
+	if loan.Status != Domain.LoanStatusApproved {
+		return nil, fmt.Errorf("%w: only approved loans can be disbursed", ErrInvalidTransition)
+	}
+
+	if input.UseProvider && d.payoutProvider == nil {
+		return nil, ErrPayoutsDisabled
+	}
+	if err == nil && existing.Status == Domain.DisbursementStatusPending && existing.Provider != "" && !input.UseProvider {
+		// The stalled payout may have gone out; only the provider can tell, through the idempotency key
+		return nil, fmt.Errorf("the payout through %s may already have been made; retry it through the provider", existing.Provider)
+	}
+
+	method := input.Method
+	if method == "" && input.UseProvider {
+		method = "bank_transfer"
+	}
+	if !containsString(Domain.DisbursementMethods, method) {
+		return nil, errors.New("invalid disbursement method")
+	}
+	if method == "bank_transfer" && input.BankAccount.AccountNumber == "" {
+		return nil, errors.New("bank account is required for bank transfers")
+	}
+
+	disbursement := &Domain.Disbursement{
+		ID:          primitive.NewObjectID(),
+		LoanID:      id,
+		Amount:      loan.Amount,
+		Currency:    loan.Currency,
+		Method:      method,
+		Status:      Domain.DisbursementStatusPending,
+		Reference:   input.Reference,
+		BankAccount: input.BankAccount,
+		DisbursedAt: input.DisbursedAt,
+		RecordedBy:  input.RecordedBy,
+		CreatedAt:   time.Now(),
+	}
+
+	if input.UseProvider {
+		disbursement.Provider = d.payoutProvider.Name()
+		disbursement.DisbursedAt = time.Time{}
+	} else {
+		if disbursement.Reference == "" {
+			return nil, errors.New("reference is required for manually recorded disbursements")
+		}
+		if disbursement.DisbursedAt.IsZero() {
+			disbursement.DisbursedAt = time.Now()
+		}
+		if disbursement.DisbursedAt.After(time.Now()) {
+			return nil, errors.New("disbursement date cannot be in the future")
+		}
+	}
+
+	claimed, err := d.disbursementRepo.Claim(disbursement, disbursementPendingTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrAlreadyDisbursing
+	}
+
+	if input.UseProvider {
+		result, err := d.payoutProvider.Transfer(infrastructure.PayoutRequest{
+			LoanID:         loanID,
+			Amount:         loan.Amount,
+			Account:        input.BankAccount,
+			IdempotencyKey: loanID,
+		})
+		if err != nil {
+			disbursement.Status = Domain.DisbursementStatusFailed
+			if updateErr := d.disbursementRepo.Update(disbursement.ID, bson.M{"status": disbursement.Status, "error": err.Error()}); updateErr != nil {
+				return nil, fmt.Errorf("payout failed: %v; %v", err, updateErr)
+			}
+			return nil, fmt.Errorf("payout failed: %v", err)
+		}
+		disbursement.Reference = result.Reference
+		disbursement.DisbursedAt = result.CompletedAt
+	}
+
+	disbursement.Status = Domain.DisbursementStatusPaid
+	err = d.disbursementRepo.Update(disbursement.ID, bson.M{
+		"status":       disbursement.Status,
+		"reference":    disbursement.Reference,
+		"disbursed_at": disbursement.DisbursedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("disbursement %s paid with reference %s but could not be recorded: %v", disbursement.ID.Hex(), disbursement.Reference, err)
+	}
+
+	if err := d.activate(&loan, disbursement, input.RecordedBy); err != nil {
+		return nil, err
+	}
+	return disbursement, nil
+}
+
+// activate rebuilds the schedule of a loan whose funds are out, moves it through
+// disbursed to active and completes the disbursement. Each step is skipped when
+// the loan is already past it, so a retry picks up where a failed one stopped.
+func (d *disbursementUsecase) activate(loan *Domain.Loan, disbursement *Domain.Disbursement, recordedBy primitive.ObjectID) error {
+	if loan.Status == Domain.LoanStatusApproved {
+		err := d.lifecycle.BuildSchedule(loan, loan.InterestRate, loan.InterestMethod, disbursement.DisbursedAt)
+		if err != nil {
+			return err
+		}
+
+		err = d.loanRepo.Update(loan.ID, bson.M{"disbursed_at": disbursement.DisbursedAt})
+		if err != nil {
+			return err
+		}
+	}
+
+	comment := fmt.Sprintf("Disbursed by %s, reference %s", disbursement.Method, disbursement.Reference)
+	for _, step := range []struct{ from, to string }{
+		{Domain.LoanStatusApproved, Domain.LoanStatusDisbursed},
+		{Domain.LoanStatusDisbursed, Domain.LoanStatusActive},
+	} {
+		if loan.Status != step.from {
+			continue
+		}
+		err := d.lifecycle.Transition(loan, Domain.LoanActorSystem, Domain.LoanStatusUpdateInput{
+			Status:    step.to,
+			ChangedBy: recordedBy,
+			Comment:   comment,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if loan.Status != Domain.LoanStatusActive {
+		return fmt.Errorf("%w: loan is %s after disbursement", ErrInvalidTransition, loan.Status)
+	}
+
+	disbursement.Status = Domain.DisbursementStatusCompleted
+	err := d.disbursementRepo.Update(disbursement.ID, bson.M{"status": disbursement.Status})
+	if err != nil {
+		return err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Disbursement",
+		Timestamp: time.Now(),
+		UserID:    recordedBy.Hex(),
+		Message:   fmt.Sprintf("Loan %s disbursed: %s by %s", loan.ID.Hex(), disbursement.Amount, disbursement.Method),
+	}
+	err = d.logRepo.Save(log)
+	if err != nil {
+		return fmt.Errorf("failed to log Loan Disbursement: %v", err)
+	}
+	return nil
+}
+
+func (d *disbursementUsecase) ViewDisbursement(loanID string) (Domain.Disbursement, error) {
+	id, err := primitive.ObjectIDFromHex(loanID)
+	if err != nil {
+		return Domain.Disbursement{}, err
+	}
+
+	return d.disbursementRepo.FindByLoanID(id)
+}
@@ -0,0 +1,183 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrEmailTemplateNotFound = errors.New("email template not found")
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+type EmailTemplateUsecase interface {
+	Render(name string, locale string, data map[string]interface{}) (Domain.EmailMessage, error)
+	GetTemplates() ([]Domain.EmailTemplate, error)
+	SetTemplate(input Domain.EmailTemplateInput) (*Domain.EmailTemplate, error)
+	DeleteTemplate(name string, locale string, deletedBy primitive.ObjectID) error
+}
+
+type emailTemplateUsecase struct {
+	templateRepo repository.EmailTemplateRepository
+	logRepo      repository.LogRepository
+	baseURL      string
+}
+
+// NewEmailTemplateUsecase renders emails from the built-in templates and the
+// admin overrides. baseURL is the public address of the service that links in
+// emails point to, available to every template as {{.BaseURL}}.
+func NewEmailTemplateUsecase(templateRepo repository.EmailTemplateRepository, logRepo repository.LogRepository, baseURL string) EmailTemplateUsecase {
+	return &emailTemplateUsecase{
+		templateRepo: templateRepo,
+		logRepo:      logRepo,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Render renders an email in the given locale. It falls back to the base
+// language (e.g. "pt" for "pt-br") and then to DefaultLocale, preferring an
+// admin override over the built-in template at each step.
+func (e *emailTemplateUsecase) Render(name string, locale string, data map[string]interface{}) (Domain.EmailMessage, error) {
+	tmpl, err := e.resolve(name, locale)
+	if err != nil {
+		return Domain.EmailMessage{}, err
+	}
+	return e.render(tmpl, data)
+}
+
+func (e *emailTemplateUsecase) render(tmpl Domain.EmailTemplate, data map[string]interface{}) (Domain.EmailMessage, error) {
+	values := map[string]interface{}{"BaseURL": e.baseURL}
+	for key, value := range data {
+		values[key] = value
+	}
+	return infrastructure.RenderEmailTemplate(tmpl, values)
+}
+
+func (e *emailTemplateUsecase) resolve(name string, locale string) (Domain.EmailTemplate, error) {
+	if !containsString(Domain.EmailTemplateNames, name) {
+		return Domain.EmailTemplate{}, ErrEmailTemplateNotFound
+	}
+
+	locale = normalizeLocale(locale)
+	candidates := []string{locale}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, language)
+	}
+	candidates = append(candidates, Domain.DefaultLocale)
+
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		override, err := e.templateRepo.Find(name, candidate)
+		if err != nil {
+			return Domain.EmailTemplate{}, err
+		}
+		if override != nil {
+			return *override, nil
+		}
+		if builtIn, ok := infrastructure.BuiltInEmailTemplate(name, candidate); ok {
+			return builtIn, nil
+		}
+	}
+	return Domain.EmailTemplate{}, ErrEmailTemplateNotFound
+}
+
+// GetTemplates lists the admin overrides together with the built-in templates
+// they have not replaced.
+func (e *emailTemplateUsecase) GetTemplates() ([]Domain.EmailTemplate, error) {
+	overrides, err := e.templateRepo.GetAllTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := map[string]bool{}
+	for _, tmpl := range overrides {
+		overridden[tmpl.Name+"/"+tmpl.Locale] = true
+	}
+	templates := overrides
+	for _, tmpl := range infrastructure.BuiltInEmailTemplates() {
+		if !overridden[tmpl.Name+"/"+tmpl.Locale] {
+			templates = append(templates, tmpl)
+		}
+	}
+	return templates, nil
+}
+
+// SetTemplate creates or replaces the override of a template for a locale.
+// It takes effect for the next email sent.
+func (e *emailTemplateUsecase) SetTemplate(input Domain.EmailTemplateInput) (*Domain.EmailTemplate, error) {
+	if !containsString(Domain.EmailTemplateNames, input.Name) {
+		return nil, fmt.Errorf("unknown email template %q", input.Name)
+	}
+	locale := normalizeLocale(input.Locale)
+	if !localePattern.MatchString(locale) {
+		return nil, errors.New("invalid locale")
+	}
+	if strings.TrimSpace(input.Subject) == "" || strings.TrimSpace(input.Text) == "" {
+		return nil, errors.New("subject and text are required")
+	}
+
+	tmpl := &Domain.EmailTemplate{
+		ID:        primitive.NewObjectID(),
+		Name:      input.Name,
+		Locale:    locale,
+		Subject:   input.Subject,
+		Text:      input.Text,
+		HTML:      input.HTML,
+		UpdatedBy: input.UpdatedBy,
+		UpdatedAt: time.Now(),
+	}
+	if err := infrastructure.ParseEmailTemplate(*tmpl); err != nil {
+		return nil, err
+	}
+	if err := e.templateRepo.Save(tmpl); err != nil {
+		return nil, err
+	}
+
+	if err := e.logTemplateChange(input.UpdatedBy, fmt.Sprintf("Email template %s (%s) overridden", tmpl.Name, tmpl.Locale)); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// DeleteTemplate removes an override, restoring the built-in template if there is one.
+func (e *emailTemplateUsecase) DeleteTemplate(name string, locale string, deletedBy primitive.ObjectID) error {
+	locale = normalizeLocale(locale)
+	deleted, err := e.templateRepo.Delete(name, locale)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEmailTemplateNotFound
+	}
+
+	return e.logTemplateChange(deletedBy, fmt.Sprintf("Email template override %s (%s) deleted", name, locale))
+}
+
+func (e *emailTemplateUsecase) logTemplateChange(userID primitive.ObjectID, message string) error {
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Email Template",
+		Timestamp: time.Now(),
+		UserID:    userID.Hex(),
+		Message:   message,
+	}
+	if err := e.logRepo.Save(log); err != nil {
+		return fmt.Errorf("failed to log Email Template: %v", err)
+	}
+	return nil
+}
+
+// normalizeLocale lower-cases a locale and accepts "_" as separator, so
+// "pt_BR" and "pt-BR" both become "pt-br".
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
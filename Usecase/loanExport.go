@@ -0,0 +1,171 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	"Loan_Tracker/infrastructure"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loanBatchSize is the number of streamed loans whose related records are looked up together.
+const loanBatchSize = 500
+
+// ExportLoans writes every loan matching the admin filters to w, one row per
+// loan with the requested columns, after a header row. Loans are read from a
+// cursor and written in batches, so the size of the export is not bounded by memory.
+func (r *reportUsecase) ExportLoans(search Domain.LoanSearch, columns []string, requestedBy primitive.ObjectID, w infrastructure.RowWriter) error {
+	if err := validateLoanSearch(&search); err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		columns = Domain.DefaultLoanExportColumns
+	}
+	needsBorrower := false
+	for _, column := range columns {
+		if !containsString(Domain.LoanExportColumns, column) {
+			return fmt.Errorf("invalid column %q", column)
+		}
+		if column == "borrower_name" || column == "borrower_email" || column == "borrower_username" {
+			needsBorrower = true
+		}
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Export",
+		Timestamp: time.Now(),
+		UserID:    requestedBy.Hex(),
+		Message:   fmt.Sprintf("loan book exported with columns %v", columns),
+	}
+	if err := r.logRepo.Save(log); err != nil {
+		return fmt.Errorf("failed to log Loan Export: %v", err)
+	}
+
+	if err := w.WriteRow(columns); err != nil {
+		return err
+	}
+
+	batch := make([]Domain.Loan, 0, loanBatchSize)
+	flush := func() error {
+		borrowers := map[primitive.ObjectID]Domain.User{}
+		if needsBorrower {
+			ids := make([]primitive.ObjectID, 0, len(batch))
+			for _, loan := range batch {
+				ids = append(ids, loan.UserID)
+			}
+			users, err := r.userRepo.FindByIDs(ids)
+			if err != nil {
+				return err
+			}
+			for _, user := range users {
+				borrowers[user.ID] = user
+			}
+		}
+
+		for _, loan := range batch {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				value, err := loanExportValue(column, loan, borrowers[loan.UserID])
+				if err != nil {
+					return err
+				}
+				row[i] = value
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := r.loanRepo.StreamLoans(search, func(loan Domain.Loan) error {
+		batch = append(batch, loan)
+		if len(batch) < loanBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// loanExportValue formats one column of a loan for export. Amounts are plain
+// decimals in the loan's currency and times are RFC 3339 in UTC.
+func loanExportValue(column string, loan Domain.Loan, borrower Domain.User) (string, error) {
+	formatTime := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	formatID := func(id primitive.ObjectID) string {
+		if id.IsZero() {
+			return ""
+		}
+		return id.Hex()
+	}
+
+	switch column {
+	case "loan_id":
+		return loan.ID.Hex(), nil
+	case "user_id":
+		return formatID(loan.UserID), nil
+	case "borrower_name":
+		return borrower.Name, nil
+	case "borrower_email":
+		return borrower.Email, nil
+	case "borrower_username":
+		return borrower.Username, nil
+	case "status":
+		return loan.Status, nil
+	case "currency":
+		return loan.Currency, nil
+	case "amount":
+		return loan.Amount.Decimal(), nil
+	case "term":
+		return strconv.Itoa(loan.Term), nil
+	case "purpose":
+		return loan.Purpose, nil
+	case "product_id":
+		return formatID(loan.ProductID), nil
+	case "product_version":
+		if loan.ProductVersion == 0 {
+			return "", nil
+		}
+		return strconv.Itoa(loan.ProductVersion), nil
+	case "interest_rate":
+		return strconv.FormatFloat(loan.InterestRate, 'f', -1, 64), nil
+	case "interest_method":
+		return loan.InterestMethod, nil
+	case "outstanding_principal":
+		return loan.OutstandingPrincipal.Decimal(), nil
+	case "outstanding_interest":
+		return loan.OutstandingInterest.Decimal(), nil
+	case "outstanding_fees":
+		return loan.OutstandingFees.Decimal(), nil
+	case "outstanding_total":
+		total, err := loan.OutstandingTotal()
+		if err != nil {
+			return "", err
+		}
+		return total.Decimal(), nil
+	case "created_at":
+		return formatTime(&loan.CreatedAt), nil
+	case "updated_at":
+		return formatTime(&loan.UpdatedAt), nil
+	case "disbursed_at":
+		return formatTime(loan.DisbursedAt), nil
+	case "first_due_date":
+		return formatTime(loan.FirstDueDate), nil
+	case "maturity_date":
+		return formatTime(loan.MaturityDate), nil
+	default:
+		return "", nil
+	}
+}
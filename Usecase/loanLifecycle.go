@@ -0,0 +1,270 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidLoanStatus      = errors.New("invalid loan status")
+	ErrInvalidTransition      = errors.New("loan status transition is not allowed")
+	ErrTransitionNotPermitted = errors.New("you are not permitted to perform this loan status transition")
+	ErrLoanNotEditable        = errors.New("loan can only be edited while it is pending")
+)
+
+// editableLoanStatuses are the statuses in which a borrower may still amend the application.
+var editableLoanStatuses = []string{Domain.LoanStatusDraft, Domain.LoanStatusSubmitted, Domain.LoanStatusPending}
+
+// loanTransition describes who may move a loan into a status and what happens when they do.
+type loanTransition struct {
+	actors []string
+	effect func(lc *loanLifecycle, loan *Domain.Loan, input Domain.LoanStatusUpdateInput) error
+}
+
+// loanTransitions is the loan state machine: current status -> target status -> transition.
+var loanTransitions = map[string]map[string]loanTransition{
+	Domain.LoanStatusDraft: {
+		Domain.LoanStatusSubmitted: {actors: []string{Domain.LoanActorBorrower}},
+		Domain.LoanStatusCancelled: {actors: []string{Domain.LoanActorBorrower}},
+	},
+	Domain.LoanStatusSubmitted: {
+		Domain.LoanStatusUnderReview: {actors: []string{Domain.LoanActorAdmin}},
+		Domain.LoanStatusWithdrawn:   {actors: []string{Domain.LoanActorBorrower}},
+	},
+	Domain.LoanStatusUnderReview: {
+		Domain.LoanStatusApproved:  {actors: []string{Domain.LoanActorAdmin}, effect: generateSchedule},
+		Domain.LoanStatusRejected:  {actors: []string{Domain.LoanActorAdmin}},
+		Domain.LoanStatusWithdrawn: {actors: []string{Domain.LoanActorBorrower}},
+	},
+	Domain.LoanStatusApproved: {
+		Domain.LoanStatusDisbursed: {actors: []string{Domain.LoanActorSystem}}, // Only through the disbursement workflow
+		Domain.LoanStatusCancelled: {actors: []string{Domain.LoanActorAdmin}},
+	},
+	Domain.LoanStatusDisbursed: {
+		Domain.LoanStatusActive: {actors: []string{Domain.LoanActorSystem}},
+	},
+	Domain.LoanStatusActive: {
+		Domain.LoanStatusPaidOff:   {actors: []string{Domain.LoanActorSystem}},
+		Domain.LoanStatusDefaulted: {actors: []string{Domain.LoanActorAdmin, Domain.LoanActorSystem}},
+	},
+	Domain.LoanStatusDefaulted: {
+		Domain.LoanStatusActive:     {actors: []string{Domain.LoanActorAdmin}},
+		Domain.LoanStatusPaidOff:    {actors: []string{Domain.LoanActorSystem}},
+		Domain.LoanStatusWrittenOff: {actors: []string{Domain.LoanActorAdmin}},
+	},
+}
+
+// loanLifecycle applies status transitions on behalf of the loan and payment use cases.
+type loanLifecycle struct {
+	loanRepo     repository.LoanRepository
+	statusRepo   repository.LoanStatusRepository
+	scheduleRepo repository.ScheduleRepository
+	productRepo  repository.LoanProductRepository
+	logRepo      repository.LogRepository
+}
+
+func newLoanLifecycle(loanRepo repository.LoanRepository, statusRepo repository.LoanStatusRepository, scheduleRepo repository.ScheduleRepository, productRepo repository.LoanProductRepository, logRepo repository.LogRepository) *loanLifecycle {
+	return &loanLifecycle{
+		loanRepo:     loanRepo,
+		statusRepo:   statusRepo,
+		scheduleRepo: scheduleRepo,
+		productRepo:  productRepo,
+		logRepo:      logRepo,
+	}
+}
+
+// CanTransition checks that the state machine allows the actor to move the loan to status.
+func (lc *loanLifecycle) CanTransition(loan *Domain.Loan, actor string, status string) (loanTransition, error) {
+	if !IsValidLoanStatus(status) {
+		return loanTransition{}, ErrInvalidLoanStatus
+	}
+
+	from := loan.Status
+	if from == Domain.LoanStatusPending {
+		from = Domain.LoanStatusSubmitted
+	}
+
+	transition, ok := loanTransitions[from][status]
+	if !ok {
+		return loanTransition{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, loan.Status, status)
+	}
+	if !containsString(transition.actors, actor) {
+		return loanTransition{}, ErrTransitionNotPermitted
+	}
+	return transition, nil
+}
+
+// Transition moves the loan to input.Status if the state machine allows the actor to do so.
+func (lc *loanLifecycle) Transition(loan *Domain.Loan, actor string, input Domain.LoanStatusUpdateInput) error {
+	transition, err := lc.CanTransition(loan, actor, input.Status)
+	if err != nil {
+		return err
+	}
+	from := loan.Status
+	if from == Domain.LoanStatusPending {
+		from = Domain.LoanStatusSubmitted
+	}
+
+	if transition.effect != nil {
+		if err := transition.effect(lc, loan, input); err != nil {
+			return err
+		}
+	}
+
+	statusUpdate := &Domain.LoanStatus{
+		ID:             primitive.NewObjectID(),
+		LoanID:         loan.ID,
+		PreviousStatus: loan.Status,
+		Status:         input.Status,
+		ChangedAt:      time.Now(),
+		ChangedBy:      input.ChangedBy,
+		Actor:          actor,
+		Comment:        input.Comment,
+	}
+
+	err = lc.loanRepo.UpdateStatus(statusUpdate)
+	if err != nil {
+		return err
+	}
+	loan.Status = input.Status
+
+	err = lc.statusRepo.Save(statusUpdate)
+	if err != nil {
+		return err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Status update",
+		Timestamp: time.Now(),
+		UserID:    input.ChangedBy.Hex(),
+		Message:   fmt.Sprintf("loan %s status updated from %s to %s by %s", loan.ID.Hex(), from, input.Status, actor),
+	}
+	err = lc.logRepo.Save(log)
+	if err != nil {
+		return fmt.Errorf("failed to log Loan status update: %v", err)
+	}
+
+	return nil
+}
+
+// RecordCreation starts the status history of a newly created loan.
+func (lc *loanLifecycle) RecordCreation(loan *Domain.Loan) error {
+	return lc.statusRepo.Save(&Domain.LoanStatus{
+		ID:        primitive.NewObjectID(),
+		LoanID:    loan.ID,
+		Status:    loan.Status,
+		ChangedAt: loan.CreatedAt,
+		ChangedBy: loan.UserID,
+		Actor:     Domain.LoanActorBorrower,
+	})
+}
+
+// actorFor resolves which lifecycle actor a user acts as for the given loan.
+func actorFor(loan *Domain.Loan, userID primitive.ObjectID, roles []string) string {
+	if Domain.HasPermission(roles, Domain.PermissionLoansApprove) {
+		return Domain.LoanActorAdmin
+	}
+	if loan.UserID == userID {
+		return Domain.LoanActorBorrower
+	}
+	return ""
+}
+
+// generateSchedule builds a provisional repayment schedule when a loan is
+// approved; it is rebuilt from the actual date once the loan is disbursed.
+func generateSchedule(lc *loanLifecycle, loan *Domain.Loan, input Domain.LoanStatusUpdateInput) error {
+	return lc.BuildSchedule(loan, input.InterestRate, input.InterestMethod, time.Now())
+}
+
+// BuildSchedule generates and stores the repayment schedule of a loan starting
+// from start. Loans made for a product use the terms of the product version
+// they were originated under; older loans use the given rate and method.
+func (lc *loanLifecycle) BuildSchedule(loan *Domain.Loan, rate float64, method string, start time.Time) error {
+	fees := Domain.NewMoney(0, loan.Currency)
+
+	if !loan.ProductID.IsZero() {
+		product, err := lc.productRepo.FindVersion(loan.ProductID, loan.ProductVersion)
+		if err != nil {
+			return err
+		}
+		rate, method, fees = product.InterestRate, product.InterestMethod, product.ProcessingFee
+		start = addMonths(start, product.GracePeriodMonths)
+	}
+	if method == "" {
+		method = Domain.InterestMethodReducingBalance
+	}
+
+	installments, err := GenerateInstallments(loan.Amount, rate, loan.Term, method, start)
+	if err != nil {
+		return err
+	}
+
+	schedule := &Domain.Schedule{
+		ID:           primitive.NewObjectID(),
+		LoanID:       loan.ID,
+		Method:       method,
+		InterestRate: rate,
+		Installments: installments,
+		GeneratedAt:  time.Now(),
+	}
+
+	totalInterest := Domain.NewMoney(0, loan.Currency)
+	for _, installment := range schedule.Installments {
+		if err := accumulate(&totalInterest, installment.Interest); err != nil {
+			return err
+		}
+	}
+
+	loan.InterestRate, loan.InterestMethod = rate, method
+	loan.OutstandingPrincipal, loan.OutstandingInterest, loan.OutstandingFees = loan.Amount, totalInterest, fees
+	loan.FirstDueDate = &installments[0].DueDate
+	loan.MaturityDate = &installments[len(installments)-1].DueDate
+
+	err = lc.loanRepo.Update(loan.ID, bson.M{
+		"interest_rate":         loan.InterestRate,
+		"interest_method":       loan.InterestMethod,
+		"outstanding_principal": loan.OutstandingPrincipal,
+		"outstanding_interest":  loan.OutstandingInterest,
+		"outstanding_fees":      loan.OutstandingFees,
+		"first_due_date":        loan.FirstDueDate,
+		"maturity_date":         loan.MaturityDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	return lc.scheduleRepo.Save(schedule)
+}
+
+// IsValidLoanStatus reports whether status is a known loan status.
+func IsValidLoanStatus(status string) bool {
+	return containsString(Domain.LoanStatuses, status)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// accumulate adds amounts to total in place, stopping at the first currency mismatch.
+func accumulate(total *Domain.Money, amounts ...Domain.Money) error {
+	for _, amount := range amounts {
+		sum, err := total.Add(amount)
+		if err != nil {
+			return err
+		}
+		*total = sum
+	}
+	return nil
+}
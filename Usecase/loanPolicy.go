@@ -0,0 +1,119 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrLoanNotFound is returned both for missing loans and for loans the requester
+// may not see, so that loan IDs cannot be probed.
+var ErrLoanNotFound = errors.New("loan not found")
+
+// ErrNotLoanOwner is returned when someone other than the borrower tries to change a loan application.
+var ErrNotLoanOwner = errors.New("only the borrower can change this loan application")
+
+// ErrOwnLoan is returned when a staff member tries to decide on or pay out a loan they are the borrower of.
+var ErrOwnLoan = errors.New("you cannot act on a loan you are the borrower of")
+
+// loanAccessPolicy decides who may read a loan and the records attached to it.
+type loanAccessPolicy struct {
+	logRepo repository.LogRepository
+}
+
+func newLoanAccessPolicy(logRepo repository.LogRepository) *loanAccessPolicy {
+	return &loanAccessPolicy{
+		logRepo: logRepo,
+	}
+}
+
+// CanView reports whether the requester owns the loan, may read every loan or is one of its designated reviewers.
+func (p *loanAccessPolicy) CanView(loan *Domain.Loan, requester Domain.Requester) bool {
+	if requester.Can(Domain.PermissionLoansRead) || loan.UserID == requester.UserID {
+		return true
+	}
+	for _, reviewerID := range loan.ReviewerIDs {
+		if reviewerID == requester.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether the requester acts as staff on the loan: they may
+// view it without being its borrower, so they also see its internal records.
+func (p *loanAccessPolicy) IsStaff(loan *Domain.Loan, requester Domain.Requester) bool {
+	return loan.UserID != requester.UserID && p.CanView(loan, requester)
+}
+
+// AuthorizeView returns ErrLoanNotFound and logs the attempt when the requester may not view the loan.
+func (p *loanAccessPolicy) AuthorizeView(loan *Domain.Loan, requester Domain.Requester) error {
+	if p.CanView(loan, requester) {
+		return nil
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "access_denied",
+		Timestamp: time.Now(),
+		UserID:    requester.UserID.Hex(),
+		Message:   fmt.Sprintf("Access to loan %s denied", loan.ID.Hex()),
+	}
+	if err := p.logRepo.Save(log); err != nil {
+		return fmt.Errorf("failed to log access denial: %v", err)
+	}
+
+	return ErrLoanNotFound
+}
+
+// AuthorizeEdit allows only the borrower who owns the loan to amend, submit or withdraw it.
+func (p *loanAccessPolicy) AuthorizeEdit(loan *Domain.Loan, requester Domain.Requester) error {
+	if loan.UserID != requester.UserID {
+		return ErrNotLoanOwner
+	}
+	return nil
+}
+
+// AuthorizeStaffAction keeps staff from deciding on their own loans.
+func (p *loanAccessPolicy) AuthorizeStaffAction(loan *Domain.Loan, userID primitive.ObjectID) error {
+	if loan.UserID == userID {
+		return ErrOwnLoan
+	}
+	return nil
+}
+
+// FindVisibleLoan loads a loan and checks that the requester may view it.
+func (p *loanAccessPolicy) FindVisibleLoan(loanRepo repository.LoanRepository, id string, requester Domain.Requester) (Domain.Loan, error) {
+	loan, err := findLoan(loanRepo, id)
+	if err != nil {
+		return Domain.Loan{}, err
+	}
+
+	if err := p.AuthorizeView(&loan, requester); err != nil {
+		return Domain.Loan{}, err
+	}
+	return loan, nil
+}
+
+// findLoan loads a loan by its hex ID, returning ErrLoanNotFound for a
+// malformed ID or a missing loan and any other error as is.
+func findLoan(loanRepo repository.LoanRepository, id string) (Domain.Loan, error) {
+	loanID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.Loan{}, ErrLoanNotFound
+	}
+
+	loan, err := loanRepo.FindByID(loanID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return Domain.Loan{}, ErrLoanNotFound
+		}
+		return Domain.Loan{}, err
+	}
+	return loan, nil
+}
@@ -0,0 +1,209 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LoanProductUsecase interface {
+	CreateProduct(input Domain.LoanProductInput) (*Domain.LoanProduct, error)
+	UpdateProduct(id string, input Domain.LoanProductInput) (*Domain.LoanProduct, error)
+	GetProduct(id string) (Domain.LoanProduct, error)
+	GetAllProducts(activeOnly bool) ([]Domain.LoanProduct, error)
+	DeleteProduct(id string) error
+}
+
+type loanProductUsecase struct {
+	productRepo repository.LoanProductRepository
+	logRepo     repository.LogRepository
+}
+
+func NewLoanProductUsecase(productRepo repository.LoanProductRepository, logRepo repository.LogRepository) LoanProductUsecase {
+	return &loanProductUsecase{
+		productRepo: productRepo,
+		logRepo:     logRepo,
+	}
+}
+
+func (p *loanProductUsecase) CreateProduct(input Domain.LoanProductInput) (*Domain.LoanProduct, error) {
+	product := &Domain.LoanProduct{
+		ID:       primitive.NewObjectID(),
+		Version:  1,
+		IsActive: true,
+	}
+	return p.saveVersion(product, input, "created")
+}
+
+// UpdateProduct saves the new terms as the next version of the product.
+func (p *loanProductUsecase) UpdateProduct(id string, input Domain.LoanProductInput) (*Domain.LoanProduct, error) {
+	productID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := p.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &Domain.LoanProduct{
+		ID:       current.ID,
+		Version:  current.Version + 1,
+		IsActive: current.IsActive,
+	}
+	return p.saveVersion(product, input, "updated")
+}
+
+func (p *loanProductUsecase) saveVersion(product *Domain.LoanProduct, input Domain.LoanProductInput, action string) (*Domain.LoanProduct, error) {
+	if err := validateProductInput(&input); err != nil {
+		return nil, err
+	}
+
+	product.Name = input.Name
+	product.Currency = input.Currency
+	product.MinAmount = input.MinAmount
+	product.MaxAmount = input.MaxAmount
+	product.AllowedTerms = input.AllowedTerms
+	product.InterestRate = input.InterestRate
+	product.InterestMethod = input.InterestMethod
+	product.ProcessingFee = input.ProcessingFee
+	product.GracePeriodMonths = input.GracePeriodMonths
+	if input.IsActive != nil {
+		product.IsActive = *input.IsActive
+	}
+	product.CreatedBy = input.CreatedBy
+	product.CreatedAt = time.Now()
+
+	err := p.productRepo.SaveVersion(product)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Product update",
+		Timestamp: time.Now(),
+		UserID:    input.CreatedBy.Hex(),
+		Message:   fmt.Sprintf("loan product %s %s (version %d)", product.ID.Hex(), action, product.Version),
+	}
+	err = p.logRepo.Save(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log Loan Product update: %v", err)
+	}
+
+	return product, nil
+}
+
+func (p *loanProductUsecase) GetProduct(id string) (Domain.LoanProduct, error) {
+	productID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.LoanProduct{}, err
+	}
+
+	return p.productRepo.FindByID(productID)
+}
+
+func (p *loanProductUsecase) GetAllProducts(activeOnly bool) ([]Domain.LoanProduct, error) {
+	return p.productRepo.GetAllProducts(activeOnly)
+}
+
+func (p *loanProductUsecase) DeleteProduct(id string) error {
+	productID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	return p.productRepo.Deactivate(productID)
+}
+
+func validateProductInput(input *Domain.LoanProductInput) error {
+	if input.Name == "" {
+		return errors.New("product name is required")
+	}
+
+	currency := input.Currency
+	for _, amount := range []Domain.Money{input.MinAmount, input.MaxAmount, input.ProcessingFee} {
+		if currency == "" && amount.Currency != "" {
+			currency = amount.Currency
+		}
+	}
+	if currency == "" {
+		currency = Domain.DefaultCurrency
+	}
+	if !Domain.IsValidCurrency(currency) {
+		return errors.New("invalid currency")
+	}
+	input.Currency = currency
+
+	var err error
+	if input.MinAmount, err = input.MinAmount.WithCurrency(currency); err != nil {
+		return err
+	}
+	if input.MaxAmount, err = input.MaxAmount.WithCurrency(currency); err != nil {
+		return err
+	}
+	if input.ProcessingFee, err = input.ProcessingFee.WithCurrency(currency); err != nil {
+		return err
+	}
+
+	cmp, err := input.MaxAmount.Cmp(input.MinAmount)
+	if err != nil {
+		return err
+	}
+	if !input.MinAmount.IsPositive() || cmp < 0 {
+		return errors.New("amount range is invalid")
+	}
+	if len(input.AllowedTerms) == 0 {
+		return errors.New("at least one term is required")
+	}
+	for _, term := range input.AllowedTerms {
+		if term <= 0 {
+			return errors.New("terms must be greater than zero")
+		}
+	}
+	if input.InterestRate < 0 {
+		return errors.New("interest rate must not be negative")
+	}
+	if input.InterestMethod == "" {
+		input.InterestMethod = Domain.InterestMethodReducingBalance
+	}
+	if input.InterestMethod != Domain.InterestMethodReducingBalance && input.InterestMethod != Domain.InterestMethodFlat {
+		return errors.New("invalid interest method")
+	}
+	if input.ProcessingFee.IsNegative() || input.GracePeriodMonths < 0 {
+		return errors.New("fees and grace period must not be negative")
+	}
+	return nil
+}
+
+// validateLoanAgainstProduct checks an application against the product it was made for.
+func validateLoanAgainstProduct(product Domain.LoanProduct, amount Domain.Money, term int) error {
+	if !product.IsActive {
+		return errors.New("loan product is not available")
+	}
+	if amount.Currency != product.Currency {
+		return fmt.Errorf("amount must be in %s", product.Currency)
+	}
+	minCmp, err := amount.Cmp(product.MinAmount)
+	if err != nil {
+		return err
+	}
+	maxCmp, err := amount.Cmp(product.MaxAmount)
+	if err != nil {
+		return err
+	}
+	if minCmp < 0 || maxCmp > 0 {
+		return fmt.Errorf("amount must be between %s and %s", product.MinAmount, product.MaxAmount)
+	}
+	for _, allowed := range product.AllowedTerms {
+		if allowed == term {
+			return nil
+		}
+	}
+	return fmt.Errorf("term must be one of %v months", product.AllowedTerms)
+}
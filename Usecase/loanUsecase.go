@@ -7,46 +7,122 @@ import (
 	"fmt"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type LoanUsecase interface {
 	ApplyForLoan(input Domain.LoanInput) (*Domain.Loan, error)
-	ViewLoanStatus(id string) (Domain.Loan, error)
-	ViewAllLoans(status string, order string) ([]Domain.Loan, error)
-	ApproveRejectLoan(id string, input Domain.LoanStatusUpdateInput) error
+	ViewLoanStatus(id string, requester Domain.Requester) (Domain.Loan, error)
+	ViewAllLoans(search Domain.LoanSearch) (Domain.LoanCursorPage, error)
+	ViewMyLoans(filter Domain.LoanFilter) (Domain.LoanPage, error)
+	ApproveRejectLoan(id string, input Domain.LoanStatusUpdateInput) (*Domain.LoanApprovalStep, error)
 	DeleteLoan(id string) error
+	ViewLoanSchedule(id string, requester Domain.Requester) (Domain.Schedule, error)
+	ViewLoanHistory(id string, requester Domain.Requester) ([]Domain.LoanStatus, error)
+	AssignReviewers(id string, input Domain.LoanReviewersInput) error
+	UpdateLoan(id string, input Domain.LoanUpdateInput, requester Domain.Requester) (Domain.Loan, error)
+	SubmitLoan(id string, requester Domain.Requester) error
+	WithdrawLoan(id string, comment string, requester Domain.Requester) error
+	ViewLoanRevisions(id string, requester Domain.Requester) ([]Domain.LoanRevision, error)
 }
 
 type loanUsecase struct {
-	loanRepo repository.LoanRepository
-	logRepo  repository.LogRepository
+	loanRepo     repository.LoanRepository
+	logRepo      repository.LogRepository
+	scheduleRepo repository.ScheduleRepository
+	statusRepo   repository.LoanStatusRepository
+	productRepo  repository.LoanProductRepository
+	revisionRepo repository.LoanRevisionRepository
+	lifecycle    *loanLifecycle
+	policy       *loanAccessPolicy
+	approvals    *loanApprovals
+	assigner     *loanAssigner
 }
 
-func NewLoanUsecase(loanRepo repository.LoanRepository, logrepo repository.LogRepository) LoanUsecase {
+func NewLoanUsecase(loanRepo repository.LoanRepository, logrepo repository.LogRepository, scheduleRepo repository.ScheduleRepository, statusRepo repository.LoanStatusRepository, productRepo repository.LoanProductRepository, revisionRepo repository.LoanRevisionRepository, approvalRepo repository.ApprovalRepository, reviewRepo repository.ReviewRepository, userRepo repository.UserRepository) LoanUsecase {
 	return &loanUsecase{
-		loanRepo: loanRepo,
-		logRepo:  logrepo,
+		loanRepo:     loanRepo,
+		logRepo:      logrepo,
+		scheduleRepo: scheduleRepo,
+		statusRepo:   statusRepo,
+		productRepo:  productRepo,
+		revisionRepo: revisionRepo,
+		lifecycle:    newLoanLifecycle(loanRepo, statusRepo, scheduleRepo, productRepo, logrepo),
+		policy:       newLoanAccessPolicy(logrepo),
+		approvals:    newLoanApprovals(approvalRepo, loanRepo, logrepo),
+		assigner:     newLoanAssigner(reviewRepo, loanRepo, userRepo, logrepo),
 	}
 }
 
 func (l *loanUsecase) ApplyForLoan(input Domain.LoanInput) (*Domain.Loan, error) {
+	if input.ProductID.IsZero() {
+		return nil, errors.New("product_id is required")
+	}
+
+	product, err := l.productRepo.FindByID(input.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := input.Amount.WithCurrency(product.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLoanAgainstProduct(product, amount, input.Term); err != nil {
+		return nil, err
+	}
+
+	zero := Domain.NewMoney(0, amount.Currency)
+	status := Domain.LoanStatusSubmitted
+	if input.Draft {
+		status = Domain.LoanStatusDraft
+	}
+
 	loan := &Domain.Loan{
-		ID:        primitive.NewObjectID(),
-		UserID:    input.UserID,
-		Amount:    input.Amount,
-		Term:      input.Term,
-		Purpose:   input.Purpose,
-		Status:    "pending", // Initial status is pending
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:             primitive.NewObjectID(),
+		UserID:         input.UserID,
+		Amount:         amount,
+		Currency:       amount.Currency,
+		Term:           input.Term,
+		Purpose:        input.Purpose,
+		Status:         status,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		ProductID:      product.ID,
+		ProductVersion: product.Version,
+		InterestRate:   product.InterestRate,
+		InterestMethod: product.InterestMethod,
+
+		OutstandingPrincipal: zero,
+		OutstandingInterest:  zero,
+		OutstandingFees:      zero,
+	}
+
+	err = l.loanRepo.Save(loan)
+	if err != nil {
+		return nil, err
 	}
 
-	err := l.loanRepo.Save(loan)
+	err = l.lifecycle.RecordCreation(loan)
 	if err != nil {
 		return nil, err
 	}
 
+	if input.Draft {
+		log := &Domain.LogEntry{
+			ID:        primitive.NewObjectID(),
+			LogType:   "Loan Application Draft",
+			Timestamp: time.Now(),
+			UserID:    input.UserID.Hex(),
+			Message:   fmt.Sprintf("Loan Application %s saved as draft", loan.ID.Hex()),
+		}
+		err = l.logRepo.Save(log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to log Loan Application Draft: %v", err)
+		}
+		return loan, nil
+	}
+
 	// Log Loan Application Submission
 	log := &Domain.LogEntry{
 		ID:        primitive.NewObjectID(),
@@ -60,93 +136,432 @@ func (l *loanUsecase) ApplyForLoan(input Domain.LoanInput) (*Domain.Loan, error)
 		return nil, fmt.Errorf("failed to log Loan Application Submission: %v", err)
 	}
 
+	l.assigner.AssignOnSubmission(loan)
+
 	return loan, nil
 }
 
-func (l *loanUsecase) ViewLoanStatus(id string) (Domain.Loan, error) {
-	loanID, err := primitive.ObjectIDFromHex(id)
+func (l *loanUsecase) ViewLoanStatus(id string, requester Domain.Requester) (Domain.Loan, error) {
+	return l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ViewMyLoans lists the loans owned by filter.UserID one page at a time.
+func (l *loanUsecase) ViewMyLoans(filter Domain.LoanFilter) (Domain.LoanPage, error) {
+	if filter.Status != "" && !IsValidLoanStatus(filter.Status) {
+		return Domain.LoanPage{}, errors.New("invalid status")
+	}
+	if filter.SortBy != "" && !containsString(Domain.LoanSortFields, filter.SortBy) {
+		return Domain.LoanPage{}, errors.New("invalid sort field")
+	}
+	if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+		return Domain.LoanPage{}, errors.New("invalid order")
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	loans, total, err := l.loanRepo.FindByUserID(filter)
 	if err != nil {
-		return Domain.Loan{}, err
+		return Domain.LoanPage{}, err
 	}
 
-	loan, err := l.loanRepo.FindByID(loanID)
+	return Domain.LoanPage{
+		Loans: loans,
+		Total: total,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+	}, nil
+}
+
+// ViewAllLoans lists loans across all borrowers one cursor page at a time.
+func (l *loanUsecase) ViewAllLoans(search Domain.LoanSearch) (Domain.LoanCursorPage, error) {
+	if err := validateLoanSearch(&search); err != nil {
+		return Domain.LoanCursorPage{}, err
+	}
+	if search.Limit < 1 {
+		search.Limit = defaultPageSize
+	}
+	if search.Limit > maxPageSize {
+		search.Limit = maxPageSize
+	}
+
+	loans, total, nextCursor, err := l.loanRepo.SearchLoans(search)
 	if err != nil {
-		return Domain.Loan{}, err
+		return Domain.LoanCursorPage{}, err
 	}
 
-	return loan, nil
+	return Domain.LoanCursorPage{
+		Loans:      loans,
+		Total:      total,
+		Limit:      search.Limit,
+		NextCursor: nextCursor,
+	}, nil
 }
 
-func (l *loanUsecase) ViewAllLoans(status string, order string) ([]Domain.Loan, error) {
-	if status != "" && status != "pending" && status != "approved" && status != "rejected" {
-		return nil, errors.New("invalid status")
+// validateLoanSearch checks the admin loan filters and puts the amount bounds
+// into the filtered currency.
+func validateLoanSearch(search *Domain.LoanSearch) error {
+	if search.Status != "" && !IsValidLoanStatus(search.Status) {
+		return errors.New("invalid status")
+	}
+	if search.SortBy != "" && !containsString(Domain.LoanSortFields, search.SortBy) {
+		return errors.New("invalid sort field")
+	}
+	if search.Order != "" && search.Order != "asc" && search.Order != "desc" {
+		return errors.New("invalid order")
+	}
+	if search.Currency != "" && !Domain.IsValidCurrency(search.Currency) {
+		return errors.New("invalid currency")
+	}
+	if search.MinAmount == nil && search.MaxAmount == nil {
+		return nil
 	}
 
-	if order != "" && order != "asc" && order != "desc" {
-		return nil, errors.New("invalid order")
+	if search.Currency == "" {
+		return errors.New("currency is required to filter by amount")
+	}
+	for _, bound := range []*Domain.Money{search.MinAmount, search.MaxAmount} {
+		if bound == nil {
+			continue
+		}
+		amount, err := bound.WithCurrency(search.Currency)
+		if err != nil {
+			return err
+		}
+		*bound = amount
+	}
+	if search.MinAmount == nil || search.MaxAmount == nil {
+		return nil
+	}
+	cmp, err := search.MinAmount.Cmp(*search.MaxAmount)
+	if err != nil {
+		return err
+	}
+	if cmp > 0 {
+		return errors.New("min_amount is greater than max_amount")
+	}
+	return nil
+}
+
+// ApproveRejectLoan applies a staff decision on a loan. Approving a loan under
+// dual control takes two users: the first approval is recorded as a
+// recommendation and leaves the loan under review, and a different user with
+// the loans:final_approve permission gives the final approval. A final
+// approval above the approver's limit escalates the loan instead. It returns
+// the approval step recorded for approvals, recommendations, escalations and
+// rejections.
+func (l *loanUsecase) ApproveRejectLoan(id string, input Domain.LoanStatusUpdateInput) (*Domain.LoanApprovalStep, error) {
+	loanID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
 	}
 
-	loans, err := l.loanRepo.GetAllLoans(status, order)
+	loan, err := l.loanRepo.FindByID(loanID)
 	if err != nil {
 		return nil, err
 	}
+	if err := l.policy.AuthorizeStaffAction(&loan, input.ChangedBy); err != nil {
+		return nil, err
+	}
+
+	actor := actorFor(&loan, input.ChangedBy, input.Roles)
+	if input.Status == Domain.LoanStatusApproved {
+		if _, err := l.lifecycle.CanTransition(&loan, actor, input.Status); err != nil {
+			return nil, err
+		}
+
+		dualControl, err := l.approvals.RequiresDualControl(&loan)
+		if err != nil {
+			return nil, err
+		}
+		if dualControl {
+			recommendation, err := l.approvals.AuthorizeFinalApproval(&loan, input.ChangedBy, input.Roles)
+			if err != nil {
+				return nil, err
+			}
+			if recommendation == nil {
+				return l.approvals.Record(&loan, Domain.ApprovalStepRecommended, input.ChangedBy, input.Comment)
+			}
+		}
+
+		limit, err := l.approvals.LimitFor(input.ChangedBy, input.Roles, loan.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if limit != nil {
+			cmp, err := loan.Amount.Cmp(*limit)
+			if err != nil {
+				return nil, err
+			}
+			if cmp > 0 {
+				if loan.Escalation != nil {
+					return nil, ErrApprovalLimit
+				}
+				return l.approvals.Escalate(&loan, input.ChangedBy, *limit, input.Comment)
+			}
+		}
+	}
 
-	return loans, nil
+	if err := l.lifecycle.Transition(&loan, actor, input); err != nil {
+		return nil, err
+	}
+
+	switch input.Status {
+	case Domain.LoanStatusApproved:
+		return l.approvals.Record(&loan, Domain.ApprovalStepApproved, input.ChangedBy, input.Comment)
+	case Domain.LoanStatusRejected:
+		return l.approvals.Record(&loan, Domain.ApprovalStepRejected, input.ChangedBy, input.Comment)
+	}
+	return nil, nil
 }
 
-func (l *loanUsecase) ApproveRejectLoan(id string, input Domain.LoanStatusUpdateInput) error {
+func (l *loanUsecase) DeleteLoan(id string) error {
 	loanID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	loan, err := l.loanRepo.FindByID(loanID)
+	err = l.loanRepo.Delete(loanID)
 	if err != nil {
 		return err
 	}
 
-	if loan.Status == "approved" || loan.Status == "rejected" {
-		return errors.New("loan has already been processed")
+	return nil
+}
+
+func (l *loanUsecase) ViewLoanSchedule(id string, requester Domain.Requester) (Domain.Schedule, error) {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+	if err != nil {
+		return Domain.Schedule{}, err
 	}
 
-	statusUpdate := &Domain.LoanStatus{
-		ID:        primitive.NewObjectID(),
-		LoanID:    loanID,
-		Status:    input.Status,
-		ChangedAt: time.Now(),
-		ChangedBy: input.ChangedBy,
+	schedule, err := l.scheduleRepo.FindByLoanID(loan.ID)
+	if err != nil {
+		return Domain.Schedule{}, err
 	}
 
-	err = l.loanRepo.UpdateStatus(statusUpdate)
+	return schedule, nil
+}
+
+func (l *loanUsecase) ViewLoanHistory(id string, requester Domain.Requester) ([]Domain.LoanStatus, error) {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := l.statusRepo.FindByLoanID(loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// AssignReviewers designates the staff members who may view a loan besides its owner and admins.
+func (l *loanUsecase) AssignReviewers(id string, input Domain.LoanReviewersInput) error {
+	loanID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := l.loanRepo.FindByID(loanID); err != nil {
+		return err
+	}
+
+	reviewerIDs := make([]primitive.ObjectID, 0, len(input.ReviewerIDs))
+	for _, hex := range input.ReviewerIDs {
+		reviewerID, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return fmt.Errorf("invalid reviewer ID %q", hex)
+		}
+		reviewerIDs = append(reviewerIDs, reviewerID)
+	}
+
+	err = l.loanRepo.Update(loanID, bson.M{"reviewer_ids": reviewerIDs, "updated_at": time.Now()})
 	if err != nil {
 		return err
 	}
 
 	log := &Domain.LogEntry{
 		ID:        primitive.NewObjectID(),
-		LogType:   "Loan Status update",
+		LogType:   "Loan Reviewers update",
 		Timestamp: time.Now(),
 		UserID:    input.ChangedBy.Hex(),
-		Message:   "loan status updated",
+		Message:   fmt.Sprintf("loan %s reviewers set to %v", id, input.ReviewerIDs),
 	}
 	err = l.logRepo.Save(log)
 	if err != nil {
-		return fmt.Errorf("failed to log Loan status update: %v", err)
+		return fmt.Errorf("failed to log Loan Reviewers update: %v", err)
 	}
 
 	return nil
 }
 
-func (l *loanUsecase) DeleteLoan(id string) error {
-	loanID, err := primitive.ObjectIDFromHex(id)
+// UpdateLoan amends the amount, term or purpose of a pending loan application
+// and records the changed fields as a new revision.
+func (l *loanUsecase) UpdateLoan(id string, input Domain.LoanUpdateInput, requester Domain.Requester) (Domain.Loan, error) {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+	if err != nil {
+		return Domain.Loan{}, err
+	}
+	if err := l.policy.AuthorizeEdit(&loan, requester); err != nil {
+		return Domain.Loan{}, err
+	}
+	if !containsString(editableLoanStatuses, loan.Status) {
+		return Domain.Loan{}, ErrLoanNotEditable
+	}
+
+	amount, term, purpose := loan.Amount, loan.Term, loan.Purpose
+	if input.Amount != nil {
+		amount, err = input.Amount.WithCurrency(loan.Currency)
+		if err != nil {
+			return Domain.Loan{}, err
+		}
+	}
+	if input.Term != nil {
+		term = *input.Term
+	}
+	if input.Purpose != nil {
+		purpose = *input.Purpose
+	}
+
+	if !loan.ProductID.IsZero() {
+		product, err := l.productRepo.FindVersion(loan.ProductID, loan.ProductVersion)
+		if err != nil {
+			return Domain.Loan{}, err
+		}
+		if err := validateLoanAgainstProduct(product, amount, term); err != nil {
+			return Domain.Loan{}, err
+		}
+	} else if !amount.IsPositive() || term <= 0 {
+		return Domain.Loan{}, errors.New("amount and term must be positive")
+	}
+
+	var changes []Domain.LoanFieldChange
+	if amount != loan.Amount {
+		changes = append(changes, Domain.LoanFieldChange{Field: "amount", Previous: loan.Amount.Decimal(), Current: amount.Decimal()})
+	}
+	if term != loan.Term {
+		changes = append(changes, Domain.LoanFieldChange{Field: "term", Previous: fmt.Sprint(loan.Term), Current: fmt.Sprint(term)})
+	}
+	if purpose != loan.Purpose {
+		changes = append(changes, Domain.LoanFieldChange{Field: "purpose", Previous: loan.Purpose, Current: purpose})
+	}
+	if len(changes) == 0 {
+		return loan, nil
+	}
+
+	loan.Amount, loan.Term, loan.Purpose = amount, term, purpose
+	loan.Version++
+	loan.UpdatedAt = time.Now()
+
+	err = l.loanRepo.Update(loan.ID, bson.M{
+		"amount":     loan.Amount,
+		"term":       loan.Term,
+		"purpose":    loan.Purpose,
+		"version":    loan.Version,
+		"updated_at": loan.UpdatedAt,
+	})
+	if err != nil {
+		return Domain.Loan{}, err
+	}
+
+	err = l.revisionRepo.Save(&Domain.LoanRevision{
+		ID:        primitive.NewObjectID(),
+		LoanID:    loan.ID,
+		Version:   loan.Version,
+		Changes:   changes,
+		ChangedBy: requester.UserID,
+		ChangedAt: loan.UpdatedAt,
+	})
+	if err != nil {
+		return Domain.Loan{}, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Application Update",
+		Timestamp: time.Now(),
+		UserID:    requester.UserID.Hex(),
+		Message:   fmt.Sprintf("loan %s updated to version %d", id, loan.Version),
+	}
+	err = l.logRepo.Save(log)
+	if err != nil {
+		return Domain.Loan{}, fmt.Errorf("failed to log Loan Application Update: %v", err)
+	}
+
+	return loan, nil
+}
+
+// SubmitLoan sends the borrower's draft for review.
+func (l *loanUsecase) SubmitLoan(id string, requester Domain.Requester) error {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
 	if err != nil {
 		return err
 	}
+	if err := l.policy.AuthorizeEdit(&loan, requester); err != nil {
+		return err
+	}
 
-	err = l.loanRepo.Delete(loanID)
+	err = l.lifecycle.Transition(&loan, Domain.LoanActorBorrower, Domain.LoanStatusUpdateInput{
+		Status:    Domain.LoanStatusSubmitted,
+		ChangedBy: requester.UserID,
+		Roles:     requester.Roles,
+	})
 	if err != nil {
 		return err
 	}
 
+	l.assigner.AssignOnSubmission(&loan)
 	return nil
 }
+
+// WithdrawLoan lets the borrower take back an application that has not been decided yet.
+// Drafts are cancelled; submitted applications are withdrawn.
+func (l *loanUsecase) WithdrawLoan(id string, comment string, requester Domain.Requester) error {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+	if err != nil {
+		return err
+	}
+	if err := l.policy.AuthorizeEdit(&loan, requester); err != nil {
+		return err
+	}
+
+	status := Domain.LoanStatusWithdrawn
+	if loan.Status == Domain.LoanStatusDraft {
+		status = Domain.LoanStatusCancelled
+	}
+
+	return l.lifecycle.Transition(&loan, Domain.LoanActorBorrower, Domain.LoanStatusUpdateInput{
+		Status:    status,
+		ChangedBy: requester.UserID,
+		Roles:     requester.Roles,
+		Comment:   comment,
+	})
+}
+
+// ViewLoanRevisions lists the borrower's edits to a loan since it was submitted.
+func (l *loanUsecase) ViewLoanRevisions(id string, requester Domain.Requester) ([]Domain.LoanRevision, error) {
+	loan, err := l.policy.FindVisibleLoan(l.loanRepo, id, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := l.revisionRepo.FindByLoanID(loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
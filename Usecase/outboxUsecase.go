@@ -0,0 +1,222 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
+	ErrOutboxMessageNotDead  = errors.New("only dead-lettered emails can be retried")
+)
+
+const (
+	// maxOutboxAttempts is how often an email is tried before it is dead-lettered.
+	maxOutboxAttempts = 8
+
+	// Failed attempts are retried after outboxBaseBackoff, doubling every
+	// attempt up to outboxMaxBackoff: 30s, 1m, 2m, ... 1h.
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = time.Hour
+
+	// outboxLock is how long a worker may spend sending a claimed email before
+	// another worker can take it over.
+	outboxLock = 5 * time.Minute
+
+	defaultOutboxLimit = 50
+	maxOutboxLimit     = 500
+)
+
+type OutboxUsecase interface {
+	Enqueue(message Domain.EmailMessage, category string) (*Domain.OutboxMessage, error)
+	Start(ctx context.Context, pollInterval time.Duration)
+	ProcessOutbox() (Domain.OutboxRunResult, error)
+	GetMessages(status string, limit int) ([]Domain.OutboxMessage, error)
+	GetMessage(id string) (*Domain.OutboxMessage, error)
+	RetryMessage(id string, requestedBy primitive.ObjectID) (*Domain.OutboxMessage, error)
+}
+
+type outboxUsecase struct {
+	outboxRepo repository.OutboxRepository
+	logRepo    repository.LogRepository
+	mailer     infrastructure.Mailer
+	wake       chan struct{}
+}
+
+func NewOutboxUsecase(outboxRepo repository.OutboxRepository, logRepo repository.LogRepository, mailer infrastructure.Mailer) OutboxUsecase {
+	return &outboxUsecase{
+		outboxRepo: outboxRepo,
+		logRepo:    logRepo,
+		mailer:     mailer,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Enqueue stores an email in the outbox for the worker to send. It only fails
+// if the outbox cannot be written, never because of the mail server.
+func (o *outboxUsecase) Enqueue(message Domain.EmailMessage, category string) (*Domain.OutboxMessage, error) {
+	if message.To == "" {
+		return nil, errors.New("email recipient is required")
+	}
+
+	now := time.Now()
+	outboxMessage := &Domain.OutboxMessage{
+		ID:            primitive.NewObjectID(),
+		Message:       message,
+		Category:      category,
+		Status:        Domain.OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := o.outboxRepo.Save(outboxMessage); err != nil {
+		return nil, err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return outboxMessage, nil
+}
+
+// Start drains the outbox in the background until ctx is cancelled: right
+// after an email is enqueued on this replica, and every pollInterval to pick
+// up retries and emails enqueued by other replicas. Claims are atomic, so
+// every replica can run a worker.
+func (o *outboxUsecase) Start(ctx context.Context, pollInterval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			result, err := o.ProcessOutbox()
+			if err != nil {
+				log.Printf("Email outbox: %v", err)
+			}
+			if result.DeadLettered > 0 {
+				log.Printf("Email outbox dead-lettered %d emails", result.DeadLettered)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-o.wake:
+			}
+		}
+	}()
+}
+
+// ProcessOutbox sends every email that is due, scheduling a retry with
+// exponential backoff for those that fail and dead-lettering those that
+// have failed maxOutboxAttempts times.
+func (o *outboxUsecase) ProcessOutbox() (Domain.OutboxRunResult, error) {
+	var result Domain.OutboxRunResult
+	for {
+		message, err := o.outboxRepo.ClaimNext(outboxLock)
+		if err != nil || message == nil {
+			return result, err
+		}
+
+		sendErr := o.mailer.Send(message.Message)
+		if sendErr == nil {
+			if err := o.outboxRepo.MarkSent(message.ID); err != nil {
+				return result, err
+			}
+			result.Sent++
+			continue
+		}
+
+		status, nextAttemptAt := Domain.OutboxStatusPending, time.Now().Add(outboxBackoff(message.Attempts))
+		if message.Attempts >= maxOutboxAttempts {
+			status, nextAttemptAt = Domain.OutboxStatusDead, time.Now()
+			result.DeadLettered++
+		} else {
+			result.Retrying++
+		}
+		if err := o.outboxRepo.MarkFailed(message.ID, status, nextAttemptAt, sendErr.Error()); err != nil {
+			return result, err
+		}
+	}
+}
+
+// outboxBackoff is the delay before the next attempt after the given number of failed ones.
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
+
+// GetMessages lists the most recent outbox messages, optionally with one status.
+func (o *outboxUsecase) GetMessages(status string, limit int) ([]Domain.OutboxMessage, error) {
+	if status != "" && !containsString(Domain.OutboxStatuses, status) {
+		return nil, errors.New("invalid status")
+	}
+	if limit <= 0 {
+		limit = defaultOutboxLimit
+	}
+	if limit > maxOutboxLimit {
+		limit = maxOutboxLimit
+	}
+	return o.outboxRepo.FindByStatus(status, limit)
+}
+
+func (o *outboxUsecase) GetMessage(id string) (*Domain.OutboxMessage, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrOutboxMessageNotFound
+	}
+	message, err := o.outboxRepo.FindByID(objectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrOutboxMessageNotFound
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+// RetryMessage puts a dead-lettered email back in the outbox with a fresh set of attempts.
+func (o *outboxUsecase) RetryMessage(id string, requestedBy primitive.ObjectID) (*Domain.OutboxMessage, error) {
+	message, err := o.GetMessage(id)
+	if err != nil {
+		return nil, err
+	}
+	if message.Status != Domain.OutboxStatusDead {
+		return nil, ErrOutboxMessageNotDead
+	}
+	message, err = o.outboxRepo.Requeue(message.ID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, ErrOutboxMessageNotDead
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Email Retry",
+		Timestamp: time.Now(),
+		UserID:    requestedBy.Hex(),
+		Message:   fmt.Sprintf("Dead-lettered %s email %s to %s requeued", message.Category, message.ID.Hex(), message.Message.To),
+	}
+	if err := o.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Email Retry: %v", err)
+	}
+	return message, nil
+}
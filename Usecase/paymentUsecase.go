@@ -0,0 +1,254 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PaymentUsecase interface {
+	RecordPayment(loanID string, input Domain.PaymentInput) (*Domain.Payment, error)
+	ViewPayments(loanID string, requester Domain.Requester) ([]Domain.Payment, error)
+}
+
+type paymentUsecase struct {
+	paymentRepo  repository.PaymentRepository
+	loanRepo     repository.LoanRepository
+	scheduleRepo repository.ScheduleRepository
+	logRepo      repository.LogRepository
+	lifecycle    *loanLifecycle
+	policy       *loanAccessPolicy
+}
+
+func NewPaymentUsecase(paymentRepo repository.PaymentRepository, loanRepo repository.LoanRepository, scheduleRepo repository.ScheduleRepository, statusRepo repository.LoanStatusRepository, productRepo repository.LoanProductRepository, logRepo repository.LogRepository) PaymentUsecase {
+	return &paymentUsecase{
+		paymentRepo:  paymentRepo,
+		loanRepo:     loanRepo,
+		scheduleRepo: scheduleRepo,
+		logRepo:      logRepo,
+		lifecycle:    newLoanLifecycle(loanRepo, statusRepo, scheduleRepo, productRepo, logRepo),
+		policy:       newLoanAccessPolicy(logRepo),
+	}
+}
+
+// RecordPayment applies a repayment to the loan's fees first, then to the
+// interest and principal of the oldest unpaid installments.
+func (p *paymentUsecase) RecordPayment(loanID string, input Domain.PaymentInput) (*Domain.Payment, error) {
+	id, err := primitive.ObjectIDFromHex(loanID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsString(Domain.PaymentChannels, input.Channel) {
+		return nil, errors.New("invalid payment channel")
+	}
+
+	loan, err := p.loanRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status != Domain.LoanStatusActive && loan.Status != Domain.LoanStatusDefaulted {
+		return nil, errors.New("payments can only be recorded against active or defaulted loans")
+	}
+
+	amount, err := input.Amount.WithCurrency(loan.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	outstanding, err := loan.OutstandingTotal()
+	if err != nil {
+		return nil, err
+	}
+	cmp, err := amount.Cmp(outstanding)
+	if err != nil {
+		return nil, err
+	}
+	if cmp > 0 {
+		return nil, fmt.Errorf("payment exceeds outstanding balance of %s", outstanding)
+	}
+
+	schedule, err := p.scheduleRepo.FindByLoanID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	paidAt := input.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	zero := Domain.NewMoney(0, amount.Currency)
+	payment := &Domain.Payment{
+		ID:            primitive.NewObjectID(),
+		LoanID:        id,
+		Amount:        amount,
+		Currency:      amount.Currency,
+		PaidAt:        paidAt,
+		Channel:       input.Channel,
+		Reference:     input.Reference,
+		PrincipalPaid: zero,
+		InterestPaid:  zero,
+		RecordedBy:    input.RecordedBy,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := allocatePayment(payment, loan, &schedule); err != nil {
+		return nil, err
+	}
+
+	err = p.paymentRepo.Save(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.scheduleRepo.Save(&schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.OutstandingPrincipal, err = loan.OutstandingPrincipal.Sub(payment.PrincipalPaid); err != nil {
+		return nil, err
+	}
+	if loan.OutstandingInterest, err = loan.OutstandingInterest.Sub(payment.InterestPaid); err != nil {
+		return nil, err
+	}
+	if loan.OutstandingFees, err = loan.OutstandingFees.Sub(payment.FeesPaid); err != nil {
+		return nil, err
+	}
+	err = p.loanRepo.Update(id, bson.M{
+		"outstanding_principal": loan.OutstandingPrincipal,
+		"outstanding_interest":  loan.OutstandingInterest,
+		"outstanding_fees":      loan.OutstandingFees,
+		"updated_at":            time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if outstanding, err = loan.OutstandingTotal(); err != nil {
+		return nil, err
+	}
+	if !outstanding.IsPositive() {
+		err = p.lifecycle.Transition(&loan, Domain.LoanActorSystem, Domain.LoanStatusUpdateInput{
+			Status:    Domain.LoanStatusPaidOff,
+			ChangedBy: input.RecordedBy,
+			Comment:   fmt.Sprintf("Settled by payment %s", payment.ID.Hex()),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Repayment",
+		Timestamp: time.Now(),
+		UserID:    input.RecordedBy.Hex(),
+		Message:   fmt.Sprintf("Payment of %s recorded for loan %s", payment.Amount, loanID),
+	}
+	err = p.logRepo.Save(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log Loan Repayment: %v", err)
+	}
+
+	return payment, nil
+}
+
+// allocatePayment settles the loan's outstanding fees first, then the interest
+// and principal of the oldest unpaid installments, updating the installments
+// in place and recording the split on the payment.
+func allocatePayment(payment *Domain.Payment, loan Domain.Loan, schedule *Domain.Schedule) error {
+	remaining := payment.Amount
+	fees, err := settle(&remaining, loan.OutstandingFees)
+	if err != nil {
+		return err
+	}
+	payment.FeesPaid = fees
+
+	for i := range schedule.Installments {
+		if !remaining.IsPositive() {
+			break
+		}
+		installment := &schedule.Installments[i]
+		if installment.Status == Domain.InstallmentStatusPaid {
+			continue
+		}
+
+		interestDue, err := installment.Interest.Sub(installment.InterestPaid)
+		if err != nil {
+			return err
+		}
+		interest, err := settle(&remaining, interestDue)
+		if err != nil {
+			return err
+		}
+		principalDue, err := installment.Principal.Sub(installment.PrincipalPaid)
+		if err != nil {
+			return err
+		}
+		principal, err := settle(&remaining, principalDue)
+		if err != nil {
+			return err
+		}
+
+		if err := accumulate(&installment.InterestPaid, interest); err != nil {
+			return err
+		}
+		if err := accumulate(&installment.PrincipalPaid, principal); err != nil {
+			return err
+		}
+		if interest == interestDue && principal == principalDue {
+			installment.Status = Domain.InstallmentStatusPaid
+		} else {
+			installment.Status = Domain.InstallmentStatusPartial
+		}
+
+		if err := accumulate(&payment.InterestPaid, interest); err != nil {
+			return err
+		}
+		if err := accumulate(&payment.PrincipalPaid, principal); err != nil {
+			return err
+		}
+		payment.Allocations = append(payment.Allocations, Domain.PaymentAllocation{
+			InstallmentNumber: installment.Number,
+			Principal:         principal,
+			Interest:          interest,
+		})
+	}
+	return nil
+}
+
+// settle takes as much of due as is left of remaining and returns the part taken.
+func settle(remaining *Domain.Money, due Domain.Money) (Domain.Money, error) {
+	part, err := remaining.Min(due)
+	if err != nil {
+		return Domain.Money{}, err
+	}
+	if *remaining, err = remaining.Sub(part); err != nil {
+		return Domain.Money{}, err
+	}
+	return part, nil
+}
+
+func (p *paymentUsecase) ViewPayments(loanID string, requester Domain.Requester) ([]Domain.Payment, error) {
+	loan, err := p.policy.FindVisibleLoan(p.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := p.paymentRepo.FindByLoanID(loan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,372 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrPenaltyPolicyNotFound is returned when deleting the policy of a currency that has none.
+var ErrPenaltyPolicyNotFound = errors.New("penalty policy not found")
+
+type PenaltyUsecase interface {
+	SetPolicy(input Domain.PenaltyPolicyInput) (*Domain.PenaltyPolicy, error)
+	GetPolicies() ([]Domain.PenaltyPolicy, error)
+	DeletePolicy(currency string, deletedBy primitive.ObjectID) error
+	AssessPenalties(runBy primitive.ObjectID) (Domain.PenaltySweepResult, error)
+	ViewCharges(loanID string, requester Domain.Requester) ([]Domain.LoanCharge, error)
+}
+
+type penaltyUsecase struct {
+	policyRepo   repository.PenaltyPolicyRepository
+	chargeRepo   repository.LoanChargeRepository
+	loanRepo     repository.LoanRepository
+	scheduleRepo repository.ScheduleRepository
+	logRepo      repository.LogRepository
+	policy       *loanAccessPolicy
+}
+
+func NewPenaltyUsecase(policyRepo repository.PenaltyPolicyRepository, chargeRepo repository.LoanChargeRepository, loanRepo repository.LoanRepository, scheduleRepo repository.ScheduleRepository, logRepo repository.LogRepository) PenaltyUsecase {
+	return &penaltyUsecase{
+		policyRepo:   policyRepo,
+		chargeRepo:   chargeRepo,
+		loanRepo:     loanRepo,
+		scheduleRepo: scheduleRepo,
+		logRepo:      logRepo,
+		policy:       newLoanAccessPolicy(logRepo),
+	}
+}
+
+// SetPolicy creates or replaces the penalty policy of a currency. The new
+// terms apply from today; nothing is charged retroactively for earlier days.
+func (p *penaltyUsecase) SetPolicy(input Domain.PenaltyPolicyInput) (*Domain.PenaltyPolicy, error) {
+	if !Domain.IsValidCurrency(input.Currency) {
+		return nil, errors.New("invalid currency")
+	}
+	if input.GraceDays < 0 {
+		return nil, errors.New("grace_days cannot be negative")
+	}
+	if input.PenaltyRate < 0 || input.PenaltyRate > 100 {
+		return nil, errors.New("penalty_rate must be between 0 and 100")
+	}
+
+	lateFeeAmount := Domain.NewMoney(0, input.Currency)
+	switch input.LateFeeType {
+	case "":
+	case Domain.LateFeeTypeFlat:
+		amount, err := input.LateFeeAmount.WithCurrency(input.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if !amount.IsPositive() {
+			return nil, errors.New("late_fee_amount must be greater than zero")
+		}
+		lateFeeAmount = amount
+	case Domain.LateFeeTypePercentage:
+		if input.LateFeeRate <= 0 || input.LateFeeRate > 100 {
+			return nil, errors.New("late_fee_rate must be greater than 0 and at most 100")
+		}
+	default:
+		return nil, errors.New("invalid late fee type")
+	}
+
+	policy := &Domain.PenaltyPolicy{
+		ID:            primitive.NewObjectID(),
+		Currency:      input.Currency,
+		LateFeeType:   input.LateFeeType,
+		LateFeeAmount: lateFeeAmount,
+		PenaltyRate:   input.PenaltyRate,
+		GraceDays:     input.GraceDays,
+		EffectiveFrom: startOfDay(time.Now()),
+		UpdatedBy:     input.UpdatedBy,
+		UpdatedAt:     time.Now(),
+	}
+	if input.LateFeeType == Domain.LateFeeTypePercentage {
+		policy.LateFeeRate = input.LateFeeRate
+	}
+
+	err := p.policyRepo.Save(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Penalty Policy update",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("penalty policy for %s set: late fee %q, penalty rate %v%%, %d grace days", input.Currency, input.LateFeeType, input.PenaltyRate, input.GraceDays),
+	}
+	err = p.logRepo.Save(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log Penalty Policy update: %v", err)
+	}
+
+	return policy, nil
+}
+
+func (p *penaltyUsecase) GetPolicies() ([]Domain.PenaltyPolicy, error) {
+	return p.policyRepo.GetAllPolicies()
+}
+
+func (p *penaltyUsecase) DeletePolicy(currency string, deletedBy primitive.ObjectID) error {
+	deleted, err := p.policyRepo.Delete(currency)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPenaltyPolicyNotFound
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Penalty Policy update",
+		Timestamp: time.Now(),
+		UserID:    deletedBy.Hex(),
+		Message:   fmt.Sprintf("penalty policy for %s deleted", currency),
+	}
+	err = p.logRepo.Save(log)
+	if err != nil {
+		return fmt.Errorf("failed to log Penalty Policy update: %v", err)
+	}
+	return nil
+}
+
+// AssessPenalties charges late fees and penalty interest on the overdue
+// installments of every active and defaulted loan whose currency has a
+// policy. Charges are keyed per loan, so running the sweep more than once a
+// day never charges twice.
+func (p *penaltyUsecase) AssessPenalties(runBy primitive.ObjectID) (Domain.PenaltySweepResult, error) {
+	asOf := startOfDay(time.Now())
+	result := Domain.PenaltySweepResult{AsOf: asOf}
+
+	policies, err := p.policyRepo.GetAllPolicies()
+	if err != nil {
+		return result, err
+	}
+	if len(policies) == 0 {
+		return result, nil
+	}
+	byCurrency := make(map[string]Domain.PenaltyPolicy, len(policies))
+	for _, policy := range policies {
+		byCurrency[policy.Currency] = policy
+	}
+
+	err = streamRepayingLoans(p.loanRepo, p.scheduleRepo, func(loan Domain.Loan, schedule Domain.Schedule) error {
+		policy, ok := byCurrency[loan.Currency]
+		if !ok {
+			return nil
+		}
+		result.LoansAssessed++
+
+		charges, err := p.chargeRepo.FindByLoanID(loan.ID)
+		if err != nil {
+			return err
+		}
+		for _, charge := range charges {
+			if !charge.FeesPending {
+				continue
+			}
+			// Posted by an earlier sweep that stopped before adding it to the fees
+			applied, err := p.applyCharge(loan, charge)
+			if err != nil {
+				return err
+			}
+			if applied {
+				result.ChargesPosted++
+			}
+		}
+
+		assessed, err := assessCharges(loan, schedule, policy, asOf, penaltyAccruedThrough(charges))
+		if err != nil {
+			return err
+		}
+		for _, charge := range assessed {
+			posted, err := p.postCharge(loan, charge)
+			if err != nil {
+				return err
+			}
+			if posted {
+				result.ChargesPosted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if result.ChargesPosted > 0 {
+		log := &Domain.LogEntry{
+			ID:        primitive.NewObjectID(),
+			LogType:   "Penalty Sweep",
+			Timestamp: time.Now(),
+			UserID:    runBy.Hex(),
+			Message:   fmt.Sprintf("%d charges posted to %d assessed loans as of %s", result.ChargesPosted, result.LoansAssessed, asOf.Format("2006-01-02")),
+		}
+		if err := p.logRepo.Save(log); err != nil {
+			return result, fmt.Errorf("failed to log Penalty Sweep: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// postCharge stores a charge and adds it to the loan's outstanding fees. It
+// reports false if the charge was already posted; a posted charge that was not
+// applied is picked up from the ledger by the next sweep.
+func (p *penaltyUsecase) postCharge(loan Domain.Loan, charge Domain.LoanCharge) (bool, error) {
+	charge.FeesPending = true
+	err := p.chargeRepo.Save(&charge)
+	if errors.Is(err, repository.ErrDuplicateCharge) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return p.applyCharge(loan, charge)
+}
+
+// applyCharge adds a posted charge to the loan's outstanding fees and writes it
+// to the audit log. The loan records the key of every charge applied to it, so
+// a charge is never added twice even if marking it applied fails. It reports
+// false if the charge was already applied.
+func (p *penaltyUsecase) applyCharge(loan Domain.Loan, charge Domain.LoanCharge) (bool, error) {
+	applied, err := p.loanRepo.ApplyCharge(loan.ID, charge.Key, charge.Amount)
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		log := &Domain.LogEntry{
+			ID:        primitive.NewObjectID(),
+			LogType:   "Loan Charge",
+			Timestamp: time.Now(),
+			UserID:    loan.UserID.Hex(),
+			Message:   fmt.Sprintf("%s of %s posted to loan %s: %s", charge.Type, charge.Amount, loan.ID.Hex(), charge.Reason),
+		}
+		if err := p.logRepo.Save(log); err != nil {
+			return false, fmt.Errorf("failed to log Loan Charge: %v", err)
+		}
+	}
+
+	if err := p.chargeRepo.MarkApplied(charge.ID); err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// penaltyAccruedThrough returns the day the last penalty interest charge was
+// assessed for, or the zero time if none was posted. Penalty interest has been
+// charged for the days before it, and the charge ledger is the only record of
+// that, so a failed write elsewhere can never make a sweep charge a day twice.
+func penaltyAccruedThrough(charges []Domain.LoanCharge) time.Time {
+	var through time.Time
+	for _, charge := range charges {
+		if charge.Type == Domain.ChargeTypePenaltyInterest && charge.AssessedFor.After(through) {
+			through = charge.AssessedFor
+		}
+	}
+	return through
+}
+
+// assessCharges works out the charges due on a loan on the day starting at
+// asOf. An installment is chargeable once it is still unpaid after its due
+// date and the grace days. It gets one late fee, and its unpaid principal
+// accrues penalty interest for every day from then on before asOf and not
+// before accruedThrough, the day penalty interest was last charged for.
+func assessCharges(loan Domain.Loan, schedule Domain.Schedule, policy Domain.PenaltyPolicy, asOf time.Time, accruedThrough time.Time) ([]Domain.LoanCharge, error) {
+	var charges []Domain.LoanCharge
+	effectiveFrom := startOfDay(policy.EffectiveFrom)
+
+	penalty := Domain.NewMoney(0, loan.Currency)
+	var penalized []string
+
+	for _, installment := range schedule.Installments {
+		chargeableFrom := startOfDay(installment.DueDate).AddDate(0, 0, policy.GraceDays+1)
+		if asOf.Before(chargeableFrom) {
+			break
+		}
+		unpaid, err := installment.Unpaid()
+		if err != nil {
+			return nil, err
+		}
+		if !unpaid.IsPositive() {
+			continue
+		}
+
+		if policy.LateFeeType != "" && !chargeableFrom.Before(effectiveFrom) {
+			fee := policy.LateFeeAmount
+			if policy.LateFeeType == Domain.LateFeeTypePercentage {
+				fee = unpaid.MulRate(policy.LateFeeRate / 100)
+			}
+			if fee.IsPositive() {
+				charges = append(charges, Domain.LoanCharge{
+					ID:                primitive.NewObjectID(),
+					LoanID:            loan.ID,
+					Key:               fmt.Sprintf("%s:%d", Domain.ChargeTypeLateFee, installment.Number),
+					Type:              Domain.ChargeTypeLateFee,
+					InstallmentNumber: installment.Number,
+					Amount:            fee,
+					Reason: fmt.Sprintf("installment %d due %s still had %s unpaid after %d grace days",
+						installment.Number, installment.DueDate.Format("2006-01-02"), unpaid, policy.GraceDays),
+					AssessedFor: asOf,
+					CreatedAt:   time.Now(),
+				})
+			}
+		}
+
+		unpaidPrincipal, err := installment.Principal.Sub(installment.PrincipalPaid)
+		if err != nil {
+			return nil, err
+		}
+		if policy.PenaltyRate <= 0 || !unpaidPrincipal.IsPositive() {
+			continue
+		}
+		from := chargeableFrom
+		if from.Before(effectiveFrom) {
+			from = effectiveFrom
+		}
+		if from.Before(accruedThrough) {
+			from = startOfDay(accruedThrough)
+		}
+		days := int(asOf.Sub(from).Hours() / 24)
+		if days <= 0 {
+			continue
+		}
+		if err := accumulate(&penalty, unpaidPrincipal.MulRate(policy.PenaltyRate/100/365*float64(days))); err != nil {
+			return nil, err
+		}
+		penalized = append(penalized, fmt.Sprintf("installment %d (%s overdue principal, %d days)", installment.Number, unpaidPrincipal, days))
+	}
+
+	if penalty.IsPositive() {
+		charges = append(charges, Domain.LoanCharge{
+			ID:     primitive.NewObjectID(),
+			LoanID: loan.ID,
+			Key:    fmt.Sprintf("%s:%s", Domain.ChargeTypePenaltyInterest, asOf.Format("2006-01-02")),
+			Type:   Domain.ChargeTypePenaltyInterest,
+			Amount: penalty,
+			Reason: fmt.Sprintf("penalty interest at %v%% a year up to %s on %s",
+				policy.PenaltyRate, asOf.Format("2006-01-02"), strings.Join(penalized, ", ")),
+			AssessedFor: asOf,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	return charges, nil
+}
+
+// ViewCharges lists the late fees and penalty interest posted to a loan.
+func (p *penaltyUsecase) ViewCharges(loanID string, requester Domain.Requester) ([]Domain.LoanCharge, error) {
+	loan, err := p.policy.FindVisibleLoan(p.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.chargeRepo.FindByLoanID(loan.ID)
+}
@@ -0,0 +1,246 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	"testing"
+	"time"
+)
+
+func usd(minorUnits int64) Domain.Money {
+	return Domain.NewMoney(minorUnits, "USD")
+}
+
+func overdueInstallment(number int, due time.Time, principalPaid int64, interestPaid int64) Domain.Installment {
+	return Domain.Installment{
+		Number:        number,
+		DueDate:       due,
+		Principal:     usd(100000),
+		Interest:      usd(1000),
+		Total:         usd(101000),
+		PrincipalPaid: usd(principalPaid),
+		InterestPaid:  usd(interestPaid),
+	}
+}
+
+func TestAssessCharges(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	// 36.5% a year is 0.1% a day: 1.00 USD per day on 1000.00 USD of overdue principal.
+	basePolicy := Domain.PenaltyPolicy{
+		Currency:      "USD",
+		LateFeeType:   Domain.LateFeeTypeFlat,
+		LateFeeAmount: usd(2500),
+		PenaltyRate:   36.5,
+		GraceDays:     5,
+		EffectiveFrom: day(time.January, 1),
+	}
+	first := overdueInstallment(1, day(time.March, 1), 0, 0)  // Chargeable from March 7
+	second := overdueInstallment(2, day(time.April, 1), 0, 0) // Chargeable from April 7
+
+	tests := []struct {
+		name           string
+		installments   []Domain.Installment
+		policy         func(policy *Domain.PenaltyPolicy)
+		asOf           time.Time
+		accruedThrough time.Time
+		wantFees       map[int]Domain.Money // Late fee per installment number
+		wantPenalty    Domain.Money         // Zero when no penalty interest is due
+	}{
+		{
+			name:         "within the grace days",
+			installments: []Domain.Installment{first},
+			asOf:         day(time.March, 6),
+		},
+		{
+			name:         "first chargeable day",
+			installments: []Domain.Installment{first},
+			asOf:         day(time.March, 7),
+			wantFees:     map[int]Domain.Money{1: usd(2500)},
+		},
+		{
+			name:         "flat late fee and penalty days",
+			installments: []Domain.Installment{first, second},
+			asOf:         day(time.March, 20),
+			wantFees:     map[int]Domain.Money{1: usd(2500)},
+			wantPenalty:  usd(1300),
+		},
+		{
+			name:         "no grace days",
+			installments: []Domain.Installment{first},
+			policy:       func(policy *Domain.PenaltyPolicy) { policy.GraceDays = 0 },
+			asOf:         day(time.March, 20),
+			wantFees:     map[int]Domain.Money{1: usd(2500)},
+			wantPenalty:  usd(1800),
+		},
+		{
+			name:         "percentage late fee",
+			installments: []Domain.Installment{first},
+			policy: func(policy *Domain.PenaltyPolicy) {
+				policy.LateFeeType = Domain.LateFeeTypePercentage
+				policy.LateFeeRate = 5
+			},
+			asOf:        day(time.March, 20),
+			wantFees:    map[int]Domain.Money{1: usd(5050)},
+			wantPenalty: usd(1300),
+		},
+		{
+			name:         "partially paid installment",
+			installments: []Domain.Installment{overdueInstallment(1, day(time.March, 1), 40000, 1000)},
+			policy: func(policy *Domain.PenaltyPolicy) {
+				policy.LateFeeType = Domain.LateFeeTypePercentage
+				policy.LateFeeRate = 5
+			},
+			asOf:        day(time.March, 20),
+			wantFees:    map[int]Domain.Money{1: usd(3000)},
+			wantPenalty: usd(780),
+		},
+		{
+			name:         "only interest unpaid",
+			installments: []Domain.Installment{overdueInstallment(1, day(time.March, 1), 100000, 0)},
+			asOf:         day(time.March, 20),
+			wantFees:     map[int]Domain.Money{1: usd(2500)},
+		},
+		{
+			name:         "paid installment",
+			installments: []Domain.Installment{overdueInstallment(1, day(time.March, 1), 100000, 1000), second},
+			asOf:         day(time.March, 20),
+		},
+		{
+			name:         "two overdue installments",
+			installments: []Domain.Installment{first, second},
+			asOf:         day(time.April, 20),
+			wantFees:     map[int]Domain.Money{1: usd(2500), 2: usd(2500)},
+			wantPenalty:  usd(4400 + 1300),
+		},
+		{
+			name:           "penalty already charged for earlier days",
+			installments:   []Domain.Installment{first},
+			asOf:           day(time.March, 20),
+			accruedThrough: day(time.March, 15),
+			wantFees:       map[int]Domain.Money{1: usd(2500)},
+			wantPenalty:    usd(500),
+		},
+		{
+			name:           "penalty already charged for the day",
+			installments:   []Domain.Installment{first},
+			asOf:           day(time.March, 20),
+			accruedThrough: day(time.March, 20),
+			wantFees:       map[int]Domain.Money{1: usd(2500)},
+		},
+		{
+			name:         "policy set after the installment became chargeable",
+			installments: []Domain.Installment{first},
+			policy: func(policy *Domain.PenaltyPolicy) {
+				policy.EffectiveFrom = time.Date(2024, time.March, 10, 15, 30, 0, 0, time.UTC)
+			},
+			asOf:        day(time.March, 20),
+			wantPenalty: usd(1000),
+		},
+		{
+			name:         "no late fee",
+			installments: []Domain.Installment{first},
+			policy:       func(policy *Domain.PenaltyPolicy) { policy.LateFeeType = "" },
+			asOf:         day(time.March, 20),
+			wantPenalty:  usd(1300),
+		},
+		{
+			name:         "no penalty interest",
+			installments: []Domain.Installment{first},
+			policy:       func(policy *Domain.PenaltyPolicy) { policy.PenaltyRate = 0 },
+			asOf:         day(time.March, 20),
+			wantFees:     map[int]Domain.Money{1: usd(2500)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := basePolicy
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			loan := Domain.Loan{Currency: "USD"}
+			schedule := Domain.Schedule{Installments: tt.installments}
+
+			charges, err := assessCharges(loan, schedule, policy, tt.asOf, tt.accruedThrough)
+			if err != nil {
+				t.Fatalf("assessCharges returned error: %v", err)
+			}
+
+			fees := map[int]Domain.Money{}
+			penalty := usd(0)
+			for _, charge := range charges {
+				if !charge.AssessedFor.Equal(tt.asOf) {
+					t.Errorf("%s charge assessed for %s, want %s", charge.Type, charge.AssessedFor, tt.asOf)
+				}
+				switch charge.Type {
+				case Domain.ChargeTypeLateFee:
+					if _, ok := fees[charge.InstallmentNumber]; ok {
+						t.Errorf("installment %d got more than one late fee", charge.InstallmentNumber)
+					}
+					fees[charge.InstallmentNumber] = charge.Amount
+				case Domain.ChargeTypePenaltyInterest:
+					if !penalty.IsZero() {
+						t.Errorf("got more than one penalty interest charge")
+					}
+					penalty = charge.Amount
+				default:
+					t.Errorf("unexpected charge type %q", charge.Type)
+				}
+			}
+
+			if len(fees) != len(tt.wantFees) {
+				t.Errorf("late fees = %v, want %v", fees, tt.wantFees)
+			}
+			for number, want := range tt.wantFees {
+				if fees[number] != want {
+					t.Errorf("late fee of installment %d = %s, want %s", number, fees[number], want)
+				}
+			}
+			wantPenalty := tt.wantPenalty
+			if wantPenalty.Currency == "" {
+				wantPenalty = usd(0)
+			}
+			if penalty != wantPenalty {
+				t.Errorf("penalty interest = %s, want %s", penalty, wantPenalty)
+			}
+		})
+	}
+}
+
+func TestPenaltyAccruedThrough(t *testing.T) {
+	march := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		charges []Domain.LoanCharge
+		want    time.Time
+	}{
+		{name: "no charges", want: time.Time{}},
+		{
+			name:    "only late fees",
+			charges: []Domain.LoanCharge{{Type: Domain.ChargeTypeLateFee, AssessedFor: march(20)}},
+			want:    time.Time{},
+		},
+		{
+			name: "latest penalty interest charge",
+			charges: []Domain.LoanCharge{
+				{Type: Domain.ChargeTypePenaltyInterest, AssessedFor: march(18)},
+				{Type: Domain.ChargeTypePenaltyInterest, AssessedFor: march(20)},
+				{Type: Domain.ChargeTypePenaltyInterest, AssessedFor: march(19)},
+				{Type: Domain.ChargeTypeLateFee, AssessedFor: march(21)},
+			},
+			want: march(20),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := penaltyAccruedThrough(tt.charges); !got.Equal(tt.want) {
+				t.Errorf("penaltyAccruedThrough = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
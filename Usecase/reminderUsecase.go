@@ -0,0 +1,340 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// reminderCatchUpDays is how long after its send day a reminder is still
+	// sent, so that a day the job did not run does not lose it.
+	reminderCatchUpDays = 7
+
+	// maxReminderAttempts bounds how often a failed reminder is retried.
+	maxReminderAttempts = 3
+
+	// reminderSendingTimeout is how long a reminder may stay in the sending
+	// state before another run treats the send as lost and claims it again.
+	reminderSendingTimeout = 10 * time.Minute
+
+	maxReminderDaysBefore = 60
+)
+
+type ReminderUsecase interface {
+	GetSettings() (Domain.ReminderSettings, error)
+	UpdateSettings(input Domain.ReminderSettings) (Domain.ReminderSettings, error)
+	SendReminders() (Domain.ReminderRunResult, error)
+	ViewDeliveries(loanID string, requester Domain.Requester) ([]Domain.ReminderDelivery, error)
+}
+
+type reminderUsecase struct {
+	reminderRepo repository.ReminderRepository
+	loanRepo     repository.LoanRepository
+	scheduleRepo repository.ScheduleRepository
+	userRepo     repository.UserRepository
+	logRepo      repository.LogRepository
+	outbox       OutboxUsecase
+	templates    EmailTemplateUsecase
+	policy       *loanAccessPolicy
+}
+
+func NewReminderUsecase(reminderRepo repository.ReminderRepository, loanRepo repository.LoanRepository, scheduleRepo repository.ScheduleRepository, userRepo repository.UserRepository, logRepo repository.LogRepository, outbox OutboxUsecase, templates EmailTemplateUsecase) ReminderUsecase {
+	return &reminderUsecase{
+		reminderRepo: reminderRepo,
+		loanRepo:     loanRepo,
+		scheduleRepo: scheduleRepo,
+		userRepo:     userRepo,
+		logRepo:      logRepo,
+		outbox:       outbox,
+		templates:    templates,
+		policy:       newLoanAccessPolicy(logRepo),
+	}
+}
+
+// reminderTemplateNames maps reminder kinds to the email templates they are sent with.
+var reminderTemplateNames = map[string]string{
+	Domain.ReminderKindUpcoming: Domain.EmailTemplatePaymentUpcoming,
+	Domain.ReminderKindDue:      Domain.EmailTemplatePaymentDue,
+	Domain.ReminderKindOverdue:  Domain.EmailTemplatePaymentOverdue,
+}
+
+func (r *reminderUsecase) GetSettings() (Domain.ReminderSettings, error) {
+	return r.reminderRepo.GetSettings()
+}
+
+// UpdateSettings replaces the reminder settings. They apply from the next run.
+func (r *reminderUsecase) UpdateSettings(input Domain.ReminderSettings) (Domain.ReminderSettings, error) {
+	days := make([]int, 0, len(input.DaysBefore))
+	for _, day := range input.DaysBefore {
+		if day < 1 || day > maxReminderDaysBefore {
+			return Domain.ReminderSettings{}, fmt.Errorf("days_before must be between 1 and %d", maxReminderDaysBefore)
+		}
+		if !containsInt(days, day) {
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+	if input.SendOverdue && input.OverdueAfterDays < 1 {
+		return Domain.ReminderSettings{}, errors.New("overdue_after_days must be at least 1")
+	}
+
+	settings := Domain.ReminderSettings{
+		DaysBefore:       days,
+		SendOnDueDate:    input.SendOnDueDate,
+		SendOverdue:      input.SendOverdue,
+		OverdueAfterDays: input.OverdueAfterDays,
+		UpdatedBy:        input.UpdatedBy,
+		UpdatedAt:        time.Now(),
+	}
+	if err := r.reminderRepo.SaveSettings(&settings); err != nil {
+		return Domain.ReminderSettings{}, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Reminder Settings",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("Reminders set to %v days before, on due date %t, overdue %t after %d days", days, settings.SendOnDueDate, settings.SendOverdue, settings.OverdueAfterDays),
+	}
+	if err := r.logRepo.Save(log); err != nil {
+		return Domain.ReminderSettings{}, fmt.Errorf("failed to log Reminder Settings: %v", err)
+	}
+	return settings, nil
+}
+
+// pendingReminder is a reminder that is due to be sent on this run.
+type pendingReminder struct {
+	delivery Domain.ReminderDelivery
+	email    map[string]interface{}
+}
+
+// SendReminders emails the reminders due today for every unpaid installment of
+// the repaying loans. A reminder is recorded before it is sent, so it goes out
+// at most once even if the job runs several times a day; failed sends are
+// retried on later runs.
+func (r *reminderUsecase) SendReminders() (Domain.ReminderRunResult, error) {
+	asOf := startOfDay(time.Now())
+	result := Domain.ReminderRunResult{AsOf: asOf}
+
+	settings, err := r.reminderRepo.GetSettings()
+	if err != nil {
+		return result, err
+	}
+
+	var pending []pendingReminder
+	err = streamRepayingLoans(r.loanRepo, r.scheduleRepo, func(loan Domain.Loan, schedule Domain.Schedule) error {
+		for _, installment := range schedule.Installments {
+			if installment.Status == Domain.InstallmentStatusPaid {
+				continue
+			}
+			unpaid, err := installment.Unpaid()
+			if err != nil {
+				return err
+			}
+			if !unpaid.IsPositive() {
+				continue
+			}
+			kind, daysBefore, ok := reminderFor(installment.DueDate, asOf, settings)
+			if !ok {
+				continue
+			}
+			dueDay := startOfDay(installment.DueDate)
+			pending = append(pending, pendingReminder{
+				delivery: Domain.ReminderDelivery{
+					ID:                primitive.NewObjectID(),
+					Key:               fmt.Sprintf("%s:%d:%s:%d", loan.ID.Hex(), installment.Number, kind, daysBefore),
+					LoanID:            loan.ID,
+					UserID:            loan.UserID,
+					InstallmentNumber: installment.Number,
+					DueDate:           installment.DueDate,
+					Kind:              kind,
+					DaysBefore:        daysBefore,
+				},
+				email: map[string]interface{}{
+					"LoanID":      loan.ID.Hex(),
+					"Installment": installment.Number,
+					"DueDate":     dueDay.Format("January 2, 2006"),
+					"Amount":      unpaid.String(),
+					"DaysLeft":    int(dueDay.Sub(asOf).Hours() / 24),
+					"DaysOverdue": int(asOf.Sub(dueDay).Hours() / 24),
+				},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	borrowers, err := r.findBorrowers(pending)
+	if err != nil {
+		return result, err
+	}
+
+	for _, reminder := range pending {
+		borrower, ok := borrowers[reminder.delivery.UserID]
+		if !ok {
+			continue
+		}
+		status, err := r.deliver(reminder, borrower)
+		if err != nil {
+			return result, err
+		}
+		switch status {
+		case Domain.ReminderStatusQueued:
+			result.Queued++
+		case Domain.ReminderStatusSuppressed:
+			result.Suppressed++
+		case Domain.ReminderStatusFailed:
+			result.Failed++
+		}
+	}
+
+	if result.Queued > 0 || result.Failed > 0 {
+		log := &Domain.LogEntry{
+			ID:        primitive.NewObjectID(),
+			LogType:   "Payment Reminders",
+			Timestamp: time.Now(),
+			Message:   fmt.Sprintf("%d reminders queued, %d suppressed and %d failed as of %s", result.Queued, result.Suppressed, result.Failed, asOf.Format("2006-01-02")),
+		}
+		if err := r.logRepo.Save(log); err != nil {
+			return result, fmt.Errorf("failed to log Payment Reminders: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// deliver claims a reminder and queues it, unless it was already queued or the
+// borrower opted out of it. A claim left sending by a replica that stopped
+// is taken over after reminderSendingTimeout. It returns the resulting delivery status, or ""
+// if the reminder was not claimed.
+func (r *reminderUsecase) deliver(reminder pendingReminder, borrower Domain.User) (string, error) {
+	delivery := reminder.delivery
+	delivery.Email = borrower.Email
+
+	claimed, err := r.reminderRepo.ClaimDelivery(&delivery, maxReminderAttempts, reminderSendingTimeout)
+	if err != nil || !claimed {
+		return "", err
+	}
+
+	status, messageID, deliveryErr := Domain.ReminderStatusQueued, primitive.NilObjectID, ""
+	if delivery.Kind != Domain.ReminderKindOverdue && borrower.OptOutNonEssential {
+		status = Domain.ReminderStatusSuppressed
+	} else {
+		messageID, err = r.queueReminder(delivery, borrower, reminder.email)
+		if err != nil {
+			status, deliveryErr = Domain.ReminderStatusFailed, err.Error()
+		}
+	}
+
+	if err := r.reminderRepo.UpdateDeliveryStatus(delivery.Key, status, messageID, deliveryErr); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// queueReminder renders a reminder in the borrower's language and hands it to
+// the email outbox, returning the ID of the outbox message.
+func (r *reminderUsecase) queueReminder(delivery Domain.ReminderDelivery, borrower Domain.User, data map[string]interface{}) (primitive.ObjectID, error) {
+	name := reminderTemplateNames[delivery.Kind]
+	data["Name"] = borrower.Name
+	message, err := r.templates.Render(name, borrower.Locale, data)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	message.To = delivery.Email
+
+	queued, err := r.outbox.Enqueue(message, name)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return queued.ID, nil
+}
+
+// findBorrowers loads the borrowers of the pending reminders by ID.
+func (r *reminderUsecase) findBorrowers(pending []pendingReminder) (map[primitive.ObjectID]Domain.User, error) {
+	borrowers := map[primitive.ObjectID]Domain.User{}
+	var ids []primitive.ObjectID
+	seen := map[primitive.ObjectID]bool{}
+	for _, reminder := range pending {
+		if !seen[reminder.delivery.UserID] {
+			seen[reminder.delivery.UserID] = true
+			ids = append(ids, reminder.delivery.UserID)
+		}
+	}
+
+	for start := 0; start < len(ids); start += loanBatchSize {
+		end := min(start+loanBatchSize, len(ids))
+		users, err := r.userRepo.FindByIDs(ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			borrowers[user.ID] = user
+		}
+	}
+	return borrowers, nil
+}
+
+// reminderFor decides which reminder, if any, is due for an installment on
+// asOf. Each reminder has a send day: days_before ahead of the due date, the
+// due date itself, or overdue_after_days after it. The latest send day that
+// has passed wins, as long as it is within reminderCatchUpDays; reminders
+// ahead of the due date are no longer sent once it has arrived.
+func reminderFor(dueDate time.Time, asOf time.Time, settings Domain.ReminderSettings) (string, int, bool) {
+	dueDay := startOfDay(dueDate)
+	recent := func(sendDay time.Time) bool {
+		return !asOf.Before(sendDay) && asOf.Before(sendDay.AddDate(0, 0, reminderCatchUpDays))
+	}
+
+	if settings.SendOverdue && settings.OverdueAfterDays > 0 {
+		sendDay := dueDay.AddDate(0, 0, settings.OverdueAfterDays)
+		if recent(sendDay) {
+			return Domain.ReminderKindOverdue, 0, true
+		}
+	}
+	if asOf.After(dueDay) {
+		return "", 0, false
+	}
+	if settings.SendOnDueDate && asOf.Equal(dueDay) {
+		return Domain.ReminderKindDue, 0, true
+	}
+	if asOf.Equal(dueDay) {
+		return "", 0, false
+	}
+
+	days := append([]int(nil), settings.DaysBefore...)
+	sort.Ints(days)
+	for _, daysBefore := range days {
+		if recent(dueDay.AddDate(0, 0, -daysBefore)) {
+			return Domain.ReminderKindUpcoming, daysBefore, true
+		}
+	}
+	return "", 0, false
+}
+
+// ViewDeliveries lists the reminder emails sent for a loan.
+func (r *reminderUsecase) ViewDeliveries(loanID string, requester Domain.Requester) ([]Domain.ReminderDelivery, error) {
+	loan, err := r.policy.FindVisibleLoan(r.loanRepo, loanID, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.reminderRepo.FindByLoanID(loan.ID)
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrFXRateNotFound is returned when deleting the rate of a pair that has none.
+var ErrFXRateNotFound = errors.New("fx rate not found")
+
+type ReportUsecase interface {
+	Portfolio(query Domain.PortfolioQuery) (Domain.PortfolioReport, error)
+	SetFXRate(input Domain.FXRateInput) (*Domain.FXRate, error)
+	GetFXRates() ([]Domain.FXRate, error)
+	DeleteFXRate(from string, to string, deletedBy primitive.ObjectID) error
+	ExportLoans(search Domain.LoanSearch, columns []string, requestedBy primitive.ObjectID, w infrastructure.RowWriter) error
+	DelinquencyReport(includeLoans bool) (Domain.DelinquencyReport, error)
+	ClassifyDelinquency(runBy primitive.ObjectID) (Domain.DelinquencyReport, error)
+	DelinquencySnapshot(date time.Time) (Domain.DelinquencyReport, error)
+	ExportDelinquency(w infrastructure.RowWriter) error
+}
+
+type reportUsecase struct {
+	loanRepo        repository.LoanRepository
+	fxRateRepo      repository.FXRateRepository
+	logRepo         repository.LogRepository
+	userRepo        repository.UserRepository
+	scheduleRepo    repository.ScheduleRepository
+	delinquencyRepo repository.DelinquencyRepository
+}
+
+func NewReportUsecase(loanRepo repository.LoanRepository, fxRateRepo repository.FXRateRepository, logRepo repository.LogRepository, userRepo repository.UserRepository, scheduleRepo repository.ScheduleRepository, delinquencyRepo repository.DelinquencyRepository) ReportUsecase {
+	return &reportUsecase{
+		loanRepo:        loanRepo,
+		fxRateRepo:      fxRateRepo,
+		logRepo:         logRepo,
+		userRepo:        userRepo,
+		scheduleRepo:    scheduleRepo,
+		delinquencyRepo: delinquencyRepo,
+	}
+}
+
+// approvedLoanStatuses are the statuses of loans that were approved at some point.
+var approvedLoanStatuses = []string{
+	Domain.LoanStatusApproved, Domain.LoanStatusDisbursed, Domain.LoanStatusActive,
+	Domain.LoanStatusPaidOff, Domain.LoanStatusDefaulted, Domain.LoanStatusWrittenOff,
+}
+
+// maxSeriesPoints bounds the length of the applications time series.
+const maxSeriesPoints = 1000
+
+// Portfolio summarises the loan book: totals per currency and per status, the
+// approval rate, averages and a time series of applications. When a reporting
+// currency is given the totals are also converted into it with the FX rate table.
+func (r *reportUsecase) Portfolio(query Domain.PortfolioQuery) (Domain.PortfolioReport, error) {
+	series, err := r.applicationSeries(query)
+	if err != nil {
+		return Domain.PortfolioReport{}, err
+	}
+
+	totals, err := r.loanRepo.TotalsByCurrency()
+	if err != nil {
+		return Domain.PortfolioReport{}, err
+	}
+	byStatus, err := r.loanRepo.TotalsByStatus()
+	if err != nil {
+		return Domain.PortfolioReport{}, err
+	}
+
+	report := Domain.PortfolioReport{
+		Totals:       totals,
+		ByStatus:     byStatus,
+		Applications: series,
+		GeneratedAt:  time.Now(),
+	}
+
+	approved, rejected := 0, 0
+	for _, total := range byStatus {
+		switch {
+		case containsString(approvedLoanStatuses, total.Status):
+			approved += total.LoanCount
+		case total.Status == Domain.LoanStatusRejected:
+			rejected += total.LoanCount
+		}
+	}
+	if approved+rejected > 0 {
+		report.ApprovalRate = float64(approved) / float64(approved+rejected)
+	}
+
+	loanCount, termSum := 0, 0.0
+	for _, total := range totals {
+		loanCount += total.LoanCount
+		termSum += total.AverageTerm * float64(total.LoanCount)
+	}
+	if loanCount > 0 {
+		report.AverageTerm = termSum / float64(loanCount)
+	}
+
+	if query.ReportingCurrency == "" {
+		return report, nil
+	}
+
+	converted, err := r.convertTotals(totals, query.ReportingCurrency)
+	if err != nil {
+		return Domain.PortfolioReport{}, err
+	}
+	report.ReportingCurrency = query.ReportingCurrency
+	report.Converted = &converted
+
+	return report, nil
+}
+
+// applicationSeries counts loan applications per period of the requested range,
+// filling in periods without applications.
+func (r *reportUsecase) applicationSeries(query Domain.PortfolioQuery) (Domain.ApplicationSeries, error) {
+	interval := query.Interval
+	if interval == "" {
+		interval = Domain.ReportIntervalMonth
+	}
+	if !containsString(Domain.ReportIntervals, interval) {
+		return Domain.ApplicationSeries{}, errors.New("invalid interval")
+	}
+
+	to := time.Now().UTC()
+	if query.To != nil {
+		to = query.To.UTC()
+	}
+	from := to.AddDate(-1, 0, 0)
+	if query.From != nil {
+		from = query.From.UTC()
+	}
+	if !from.Before(to) {
+		return Domain.ApplicationSeries{}, errors.New("from must be before to")
+	}
+
+	var periods []time.Time
+	for period := periodStart(from, interval); period.Before(to); period = nextPeriod(period, interval) {
+		if len(periods) == maxSeriesPoints {
+			return Domain.ApplicationSeries{}, fmt.Errorf("date range spans more than %d %ss", maxSeriesPoints, interval)
+		}
+		periods = append(periods, period)
+	}
+
+	counts, err := r.loanRepo.CountApplications(from, to, interval)
+	if err != nil {
+		return Domain.ApplicationSeries{}, err
+	}
+	byPeriod := make(map[int64]int, len(counts))
+	for _, count := range counts {
+		byPeriod[count.PeriodStart.Unix()] = count.Count
+	}
+
+	points := make([]Domain.ApplicationCount, 0, len(periods))
+	for _, period := range periods {
+		points = append(points, Domain.ApplicationCount{PeriodStart: period, Count: byPeriod[period.Unix()]})
+	}
+
+	return Domain.ApplicationSeries{Interval: interval, From: from, To: to, Points: points}, nil
+}
+
+// periodStart truncates t to the start of its day, Monday-based week or month in UTC.
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case Domain.ReportIntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case Domain.ReportIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextPeriod(period time.Time, interval string) time.Time {
+	switch interval {
+	case Domain.ReportIntervalWeek:
+		return period.AddDate(0, 0, 7)
+	case Domain.ReportIntervalMonth:
+		return period.AddDate(0, 1, 0)
+	default:
+		return period.AddDate(0, 0, 1)
+	}
+}
+
+func (r *reportUsecase) convertTotals(totals []Domain.CurrencyTotal, currency string) (Domain.CurrencyTotal, error) {
+	if !Domain.IsValidCurrency(currency) {
+		return Domain.CurrencyTotal{}, errors.New("invalid reporting currency")
+	}
+
+	converted := Domain.CurrencyTotal{
+		Currency:      currency,
+		Principal:     Domain.NewMoney(0, currency),
+		Disbursed:     Domain.NewMoney(0, currency),
+		Outstanding:   Domain.NewMoney(0, currency),
+		AverageAmount: Domain.NewMoney(0, currency),
+	}
+	termSum := 0.0
+	for _, total := range totals {
+		rate, err := r.rate(total.Currency, currency)
+		if err != nil {
+			return Domain.CurrencyTotal{}, err
+		}
+		converted.LoanCount += total.LoanCount
+		if err := accumulate(&converted.Principal, total.Principal.Convert(currency, rate)); err != nil {
+			return Domain.CurrencyTotal{}, err
+		}
+		if err := accumulate(&converted.Disbursed, total.Disbursed.Convert(currency, rate)); err != nil {
+			return Domain.CurrencyTotal{}, err
+		}
+		if err := accumulate(&converted.Outstanding, total.Outstanding.Convert(currency, rate)); err != nil {
+			return Domain.CurrencyTotal{}, err
+		}
+		termSum += total.AverageTerm * float64(total.LoanCount)
+	}
+	if converted.LoanCount > 0 {
+		average, err := converted.Principal.Div(converted.LoanCount)
+		if err != nil {
+			return Domain.CurrencyTotal{}, err
+		}
+		converted.AverageAmount = average
+		converted.AverageTerm = termSum / float64(converted.LoanCount)
+	}
+	return converted, nil
+}
+
+// rate finds the conversion rate between two currencies, using the inverse of
+// the opposite pair when only that one is maintained. Lookup failures other
+// than a missing rate are returned as is.
+func (r *reportUsecase) rate(from string, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, err := r.fxRateRepo.FindRate(from, to)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+	rate, err = r.fxRateRepo.FindRate(to, from)
+	if err == nil {
+		return 1 / rate.Rate, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no fx rate from %s to %s", from, to)
+}
+
+func (r *reportUsecase) SetFXRate(input Domain.FXRateInput) (*Domain.FXRate, error) {
+	if !Domain.IsValidCurrency(input.FromCurrency) || !Domain.IsValidCurrency(input.ToCurrency) {
+		return nil, errors.New("invalid currency")
+	}
+	if input.FromCurrency == input.ToCurrency {
+		return nil, errors.New("currencies must differ")
+	}
+	if input.Rate <= 0 {
+		return nil, errors.New("rate must be greater than zero")
+	}
+
+	rate := &Domain.FXRate{
+		ID:           primitive.NewObjectID(),
+		FromCurrency: input.FromCurrency,
+		ToCurrency:   input.ToCurrency,
+		Rate:         input.Rate,
+		UpdatedBy:    input.UpdatedBy,
+		UpdatedAt:    time.Now(),
+	}
+
+	err := r.fxRateRepo.Save(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "FX Rate update",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("fx rate %s/%s set to %v", input.FromCurrency, input.ToCurrency, input.Rate),
+	}
+	err = r.logRepo.Save(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log FX Rate update: %v", err)
+	}
+
+	return rate, nil
+}
+
+func (r *reportUsecase) GetFXRates() ([]Domain.FXRate, error) {
+	return r.fxRateRepo.GetAllRates()
+}
+
+func (r *reportUsecase) DeleteFXRate(from string, to string, deletedBy primitive.ObjectID) error {
+	deleted, err := r.fxRateRepo.Delete(from, to)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrFXRateNotFound
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "FX Rate update",
+		Timestamp: time.Now(),
+		UserID:    deletedBy.Hex(),
+		Message:   fmt.Sprintf("fx rate %s/%s deleted", from, to),
+	}
+	err = r.logRepo.Save(log)
+	if err != nil {
+		return fmt.Errorf("failed to log FX Rate update: %v", err)
+	}
+	return nil
+}
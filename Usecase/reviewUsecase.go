@@ -0,0 +1,338 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrLoanNotInReview  = errors.New("loan is not waiting for review")
+	ErrAlreadyAssigned  = errors.New("loan is already assigned to this officer")
+	ErrInvalidOfficer   = errors.New("officer must be a user who can approve loans and not the borrower")
+	ErrNoOfficers       = errors.New("no loan officer is available for round-robin assignment")
+	ErrAssignmentTarget = errors.New("set either officer_id or round_robin")
+)
+
+const maxReviewSLAHours = 30 * 24
+
+type ReviewUsecase interface {
+	GetSettings() (Domain.ReviewSettings, error)
+	UpdateSettings(input Domain.ReviewSettings) (Domain.ReviewSettings, error)
+	AssignLoan(loanID string, input Domain.LoanAssignmentInput) (*Domain.LoanAssignmentChange, error)
+	AssignWaiting() (int, error)
+	ViewQueue(filter Domain.ReviewQueueFilter) ([]Domain.ReviewQueueItem, error)
+	ViewAssignments(loanID string) ([]Domain.LoanAssignmentChange, error)
+	FlagSLABreaches() (int64, error)
+}
+
+type reviewUsecase struct {
+	reviewRepo repository.ReviewRepository
+	loanRepo   repository.LoanRepository
+	logRepo    repository.LogRepository
+	assigner   *loanAssigner
+}
+
+func NewReviewUsecase(reviewRepo repository.ReviewRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository, logRepo repository.LogRepository) ReviewUsecase {
+	return &reviewUsecase{
+		reviewRepo: reviewRepo,
+		loanRepo:   loanRepo,
+		logRepo:    logRepo,
+		assigner:   newLoanAssigner(reviewRepo, loanRepo, userRepo, logRepo),
+	}
+}
+
+func (r *reviewUsecase) GetSettings() (Domain.ReviewSettings, error) {
+	return r.reviewRepo.GetSettings()
+}
+
+// UpdateSettings replaces the review settings. A new SLA applies to the
+// applications already waiting from the next check.
+func (r *reviewUsecase) UpdateSettings(input Domain.ReviewSettings) (Domain.ReviewSettings, error) {
+	if input.SLAHours < 1 || input.SLAHours > maxReviewSLAHours {
+		return Domain.ReviewSettings{}, fmt.Errorf("sla_hours must be between 1 and %d", maxReviewSLAHours)
+	}
+
+	settings := Domain.ReviewSettings{
+		SLAHours:   input.SLAHours,
+		AutoAssign: input.AutoAssign,
+		UpdatedBy:  input.UpdatedBy,
+		UpdatedAt:  time.Now(),
+	}
+	if err := r.reviewRepo.SaveSettings(&settings); err != nil {
+		return Domain.ReviewSettings{}, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Review Settings",
+		Timestamp: time.Now(),
+		UserID:    input.UpdatedBy.Hex(),
+		Message:   fmt.Sprintf("Review SLA set to %d hours, auto assignment %t", settings.SLAHours, settings.AutoAssign),
+	}
+	if err := r.logRepo.Save(log); err != nil {
+		return Domain.ReviewSettings{}, fmt.Errorf("failed to log Review Settings: %v", err)
+	}
+	return settings, nil
+}
+
+// AssignLoan assigns or reassigns a waiting loan to the given officer, or to
+// the next officer in the round-robin rotation.
+func (r *reviewUsecase) AssignLoan(loanID string, input Domain.LoanAssignmentInput) (*Domain.LoanAssignmentChange, error) {
+	loan, err := findLoan(r.loanRepo, loanID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(Domain.ReviewStatuses, loan.Status) {
+		return nil, ErrLoanNotInReview
+	}
+
+	if input.RoundRobin == (input.OfficerID != "") {
+		return nil, ErrAssignmentTarget
+	}
+	if input.RoundRobin {
+		return r.assigner.AssignRoundRobin(&loan, input.AssignedBy, input.Reason)
+	}
+
+	officerID, err := primitive.ObjectIDFromHex(input.OfficerID)
+	if err != nil {
+		return nil, ErrInvalidOfficer
+	}
+	return r.assigner.Assign(&loan, officerID, Domain.AssignmentMethodManual, input.AssignedBy, input.Reason)
+}
+
+// AssignWaiting assigns the unassigned applications round-robin when automatic
+// assignment is enabled, and returns how many were assigned.
+func (r *reviewUsecase) AssignWaiting() (int, error) {
+	settings, err := r.reviewRepo.GetSettings()
+	if err != nil {
+		return 0, err
+	}
+	if !settings.AutoAssign {
+		return 0, nil
+	}
+
+	loans, err := r.loanRepo.FindInReview(Domain.ReviewQueueFilter{Unassigned: true})
+	if err != nil {
+		return 0, err
+	}
+	assigned := 0
+	for i := range loans {
+		if _, err := r.assigner.AssignRoundRobin(&loans[i], primitive.NilObjectID, ""); err != nil {
+			return assigned, err
+		}
+		assigned++
+	}
+	return assigned, nil
+}
+
+// ViewQueue lists the loans waiting for a decision, oldest first, with their SLA timers.
+func (r *reviewUsecase) ViewQueue(filter Domain.ReviewQueueFilter) ([]Domain.ReviewQueueItem, error) {
+	settings, err := r.reviewRepo.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	loans, err := r.loanRepo.FindInReview(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sla := time.Duration(settings.SLAHours) * time.Hour
+	now := time.Now()
+	items := []Domain.ReviewQueueItem{}
+	for _, loan := range loans {
+		item := Domain.ReviewQueueItem{
+			Loan:         loan,
+			WaitingSince: loan.CreatedAt,
+			SLADueAt:     loan.CreatedAt.Add(sla),
+		}
+		item.Overdue = now.After(item.SLADueAt)
+		if filter.OverdueOnly && !item.Overdue {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ViewAssignments lists the assignments and reassignments of a loan.
+func (r *reviewUsecase) ViewAssignments(loanID string) ([]Domain.LoanAssignmentChange, error) {
+	loan, err := findLoan(r.loanRepo, loanID)
+	if err != nil {
+		return nil, err
+	}
+	return r.reviewRepo.FindAssignmentsByLoanID(loan.ID)
+}
+
+// FlagSLABreaches records the time each waiting application first exceeded the
+// review SLA and returns how many were flagged on this run.
+func (r *reviewUsecase) FlagSLABreaches() (int64, error) {
+	settings, err := r.reviewRepo.GetSettings()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	flagged, err := r.loanRepo.FlagReviewSLABreaches(now.Add(-time.Duration(settings.SLAHours)*time.Hour), now)
+	if err != nil {
+		return 0, err
+	}
+	if flagged == 0 {
+		return 0, nil
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Review SLA breach",
+		Timestamp: now,
+		UserID:    primitive.NilObjectID.Hex(),
+		Message:   fmt.Sprintf("%d applications waited longer than %d hours for review", flagged, settings.SLAHours),
+	}
+	if err := r.logRepo.Save(log); err != nil {
+		return flagged, fmt.Errorf("failed to log Review SLA breach: %v", err)
+	}
+	return flagged, nil
+}
+
+// loanAssigner assigns loans to officers on behalf of the review and loan use cases.
+type loanAssigner struct {
+	reviewRepo repository.ReviewRepository
+	loanRepo   repository.LoanRepository
+	userRepo   repository.UserRepository
+	logRepo    repository.LogRepository
+}
+
+func newLoanAssigner(reviewRepo repository.ReviewRepository, loanRepo repository.LoanRepository, userRepo repository.UserRepository, logRepo repository.LogRepository) *loanAssigner {
+	return &loanAssigner{
+		reviewRepo: reviewRepo,
+		loanRepo:   loanRepo,
+		userRepo:   userRepo,
+		logRepo:    logRepo,
+	}
+}
+
+// AssignOnSubmission assigns a new application round-robin if automatic
+// assignment is enabled. A failure does not undo the submission: it is
+// written to the activity log and the loan stays unassigned until the
+// review-queue job assigns it.
+func (a *loanAssigner) AssignOnSubmission(loan *Domain.Loan) {
+	settings, err := a.reviewRepo.GetSettings()
+	if err == nil && !settings.AutoAssign {
+		return
+	}
+	if err == nil {
+		_, err = a.AssignRoundRobin(loan, primitive.NilObjectID, "")
+	}
+	if err == nil {
+		return
+	}
+
+	a.logRepo.Save(&Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Assignment",
+		Timestamp: time.Now(),
+		UserID:    primitive.NilObjectID.Hex(),
+		Message:   fmt.Sprintf("loan %s could not be assigned on submission: %v", loan.ID.Hex(), err),
+	})
+}
+
+// AssignRoundRobin assigns the loan to the next loan officer in the rotation,
+// skipping the borrower and the officer it is already assigned to.
+func (a *loanAssigner) AssignRoundRobin(loan *Domain.Loan, assignedBy primitive.ObjectID, reason string) (*Domain.LoanAssignmentChange, error) {
+	users, err := a.userRepo.GetAllUsers(Domain.RoleLoanOfficer)
+	if err != nil {
+		return nil, err
+	}
+	officers := make([]Domain.User, 0, len(users))
+	for _, user := range users {
+		if user.ID == loan.UserID || (loan.Assignment != nil && user.ID == loan.Assignment.OfficerID) {
+			continue
+		}
+		officers = append(officers, user)
+	}
+	if len(officers) == 0 {
+		return nil, ErrNoOfficers
+	}
+	sort.Slice(officers, func(i, j int) bool { return officers[i].ID.Hex() < officers[j].ID.Hex() })
+
+	next, err := a.reviewRepo.NextRoundRobin()
+	if err != nil {
+		return nil, err
+	}
+	officer := officers[(next-1)%int64(len(officers))]
+	return a.record(loan, officer.ID, Domain.AssignmentMethodRoundRobin, assignedBy, reason)
+}
+
+// Assign assigns the loan to an officer who can approve loans and is not its borrower.
+func (a *loanAssigner) Assign(loan *Domain.Loan, officerID primitive.ObjectID, method string, assignedBy primitive.ObjectID, reason string) (*Domain.LoanAssignmentChange, error) {
+	if loan.UserID == officerID {
+		return nil, ErrInvalidOfficer
+	}
+	if loan.Assignment != nil && loan.Assignment.OfficerID == officerID {
+		return nil, ErrAlreadyAssigned
+	}
+	users, err := a.userRepo.FindByIDs([]primitive.ObjectID{officerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 || !Domain.HasPermission(users[0].Roles, Domain.PermissionLoansApprove) {
+		return nil, ErrInvalidOfficer
+	}
+	return a.record(loan, officerID, method, assignedBy, reason)
+}
+
+func (a *loanAssigner) record(loan *Domain.Loan, officerID primitive.ObjectID, method string, assignedBy primitive.ObjectID, reason string) (*Domain.LoanAssignmentChange, error) {
+	now := time.Now()
+	change := &Domain.LoanAssignmentChange{
+		ID:         primitive.NewObjectID(),
+		LoanID:     loan.ID,
+		OfficerID:  officerID,
+		Method:     method,
+		AssignedBy: assignedBy,
+		Reason:     reason,
+		AssignedAt: now,
+	}
+	if loan.Assignment != nil {
+		change.PreviousOfficerID = loan.Assignment.OfficerID
+	}
+
+	assignment := &Domain.LoanAssignment{
+		OfficerID:  officerID,
+		Method:     method,
+		AssignedBy: assignedBy,
+		AssignedAt: now,
+	}
+	if err := a.loanRepo.Update(loan.ID, bson.M{"assignment": assignment, "updated_at": now}); err != nil {
+		return nil, err
+	}
+	loan.Assignment = assignment
+
+	if err := a.reviewRepo.SaveAssignment(change); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("loan %s assigned to %s (%s)", loan.ID.Hex(), officerID.Hex(), method)
+	if !change.PreviousOfficerID.IsZero() {
+		message = fmt.Sprintf("loan %s reassigned from %s to %s (%s)", loan.ID.Hex(), change.PreviousOfficerID.Hex(), officerID.Hex(), method)
+	}
+	if reason != "" {
+		message += ": " + reason
+	}
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Loan Assignment",
+		Timestamp: now,
+		UserID:    assignedBy.Hex(),
+		Message:   message,
+	}
+	if err := a.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Loan Assignment: %v", err)
+	}
+	return change, nil
+}
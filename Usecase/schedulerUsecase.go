@@ -0,0 +1,294 @@
+package Usecases
+
+import (
+	"Loan_Tracker/Domain"
+	repository "Loan_Tracker/Repository"
+	"Loan_Tracker/infrastructure"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+)
+
+// JobFunc runs a background job and returns a short summary of what it did.
+type JobFunc func() (string, error)
+
+const (
+	// jobLeaseTTL is how long a replica may hold a job lease without renewing
+	// it. Running jobs renew their lease every third of it.
+	jobLeaseTTL = 5 * time.Minute
+
+	defaultJobRunLimit = 20
+	maxJobRunLimit     = 100
+)
+
+type SchedulerUsecase interface {
+	RegisterJob(name string, description string, schedule string, run JobFunc) error
+	Start(ctx context.Context)
+	ListJobs() ([]Domain.JobInfo, error)
+	TriggerJob(name string, triggeredBy primitive.ObjectID) (*Domain.JobRun, error)
+	ViewRuns(name string, limit int) ([]Domain.JobRun, error)
+}
+
+type scheduledJob struct {
+	name        string
+	description string
+	schedule    *infrastructure.CronSchedule
+	run         JobFunc
+	nextRunAt   time.Time
+}
+
+type schedulerUsecase struct {
+	jobRepo repository.JobRepository
+	logRepo repository.LogRepository
+	owner   string
+
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+}
+
+// NewSchedulerUsecase creates a scheduler whose jobs are coordinated with the
+// other replicas through leases stored in Mongo.
+func NewSchedulerUsecase(jobRepo repository.JobRepository, logRepo repository.LogRepository) SchedulerUsecase {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &schedulerUsecase{
+		jobRepo: jobRepo,
+		logRepo: logRepo,
+		owner:   fmt.Sprintf("%s:%d", host, os.Getpid()),
+		jobs:    map[string]*scheduledJob{},
+	}
+}
+
+// RegisterJob adds a job with a cron schedule. Jobs must be registered before Start.
+func (s *schedulerUsecase) RegisterJob(name string, description string, schedule string, run JobFunc) error {
+	if name == "" {
+		return errors.New("job name is required")
+	}
+	parsed, err := infrastructure.ParseCron(schedule)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+	s.jobs[name] = &scheduledJob{name: name, description: description, schedule: parsed, run: run}
+	return nil
+}
+
+// Start runs every registered job on its schedule until ctx is cancelled. A
+// replica that finds the job's lease held by another one skips that run.
+func (s *schedulerUsecase) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *schedulerUsecase) loop(ctx context.Context, job *scheduledJob) {
+	for {
+		next := job.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Job %s has no upcoming run for schedule %s", job.name, job.schedule)
+			return
+		}
+		s.mu.Lock()
+		job.nextRunAt = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		run, err := s.runJob(job, next, Domain.JobTriggerSchedule, primitive.NilObjectID)
+		switch {
+		case errors.Is(err, ErrJobRunning):
+		case err != nil:
+			log.Printf("Job %s could not run: %v", job.name, err)
+		case run.Status == Domain.JobRunFailed:
+			log.Printf("Job %s failed: %s", job.name, run.Error)
+		}
+	}
+}
+
+// runJob takes the job's lease, runs it and records the run in the history.
+// Scheduled runs pass the slot they were scheduled for so that only one
+// replica runs each slot; manual runs pass the zero time.
+func (s *schedulerUsecase) runJob(job *scheduledJob, slot time.Time, trigger string, triggeredBy primitive.ObjectID) (*Domain.JobRun, error) {
+	acquired, err := s.jobRepo.AcquireLease(job.name, s.owner, jobLeaseTTL, slot)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrJobRunning
+	}
+	defer func() {
+		if err := s.jobRepo.ReleaseLease(job.name, s.owner); err != nil {
+			log.Printf("Job %s: %v", job.name, err)
+		}
+	}()
+
+	run := &Domain.JobRun{
+		ID:          primitive.NewObjectID(),
+		Job:         job.name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Owner:       s.owner,
+		Status:      Domain.JobRunRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := s.jobRepo.SaveRun(run); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go s.renewLease(job.name, done)
+	result, jobErr := runSafely(job.run)
+	close(done)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Result = result
+	run.Status = Domain.JobRunSucceeded
+	if jobErr != nil {
+		run.Status = Domain.JobRunFailed
+		run.Error = jobErr.Error()
+	}
+	if err := s.jobRepo.SaveRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// renewLease keeps the lease of a running job alive until done is closed.
+func (s *schedulerUsecase) renewLease(name string, done <-chan struct{}) {
+	ticker := time.NewTicker(jobLeaseTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			held, err := s.jobRepo.RenewLease(name, s.owner, jobLeaseTTL)
+			if err != nil {
+				log.Printf("Job %s: %v", name, err)
+			} else if !held {
+				log.Printf("Job %s lost its lease while running", name)
+			}
+		}
+	}
+}
+
+// runSafely turns a panicking job into a failed run instead of crashing the service.
+func runSafely(run JobFunc) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run()
+}
+
+// ListJobs describes the registered jobs with their next and last runs.
+func (s *schedulerUsecase) ListJobs() ([]Domain.JobInfo, error) {
+	s.mu.Lock()
+	jobs := make([]Domain.JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		info := Domain.JobInfo{Name: job.name, Description: job.description, Schedule: job.schedule.String()}
+		if !job.nextRunAt.IsZero() {
+			next := job.nextRunAt
+			info.NextRunAt = &next
+		}
+		jobs = append(jobs, info)
+	}
+	s.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	for i := range jobs {
+		lease, err := s.jobRepo.FindLease(jobs[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		jobs[i].Lease = lease
+
+		runs, err := s.jobRepo.FindRuns(jobs[i].Name, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			jobs[i].LastRun = &runs[0]
+		}
+	}
+	return jobs, nil
+}
+
+// TriggerJob runs a job immediately and waits for it to finish. It returns
+// ErrJobRunning if a replica is already running the job.
+func (s *schedulerUsecase) TriggerJob(name string, triggeredBy primitive.ObjectID) (*Domain.JobRun, error) {
+	job, err := s.findJob(name)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.runJob(job, time.Time{}, Domain.JobTriggerManual, triggeredBy)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Job Triggered",
+		Timestamp: time.Now(),
+		UserID:    triggeredBy.Hex(),
+		Message:   fmt.Sprintf("Job %s was run manually and %s", name, run.Status),
+	}
+	if err := s.logRepo.Save(logEntry); err != nil {
+		return nil, fmt.Errorf("failed to log job run: %v", err)
+	}
+	return run, nil
+}
+
+// ViewRuns retrieves the most recent runs of a job, newest first.
+func (s *schedulerUsecase) ViewRuns(name string, limit int) ([]Domain.JobRun, error) {
+	if _, err := s.findJob(name); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultJobRunLimit
+	}
+	if limit > maxJobRunLimit {
+		limit = maxJobRunLimit
+	}
+	return s.jobRepo.FindRuns(name, limit)
+}
+
+func (s *schedulerUsecase) findJob(name string) (*scheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
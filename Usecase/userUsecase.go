@@ -9,6 +9,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,8 +20,20 @@ import (
 
 var jwtKey []byte
 
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidRole  = errors.New("invalid role")
+	ErrLastAdmin    = errors.New("at least one admin must remain")
+	ErrOwnAdminRole = errors.New("you cannot remove your own admin role")
+)
+
 // Initialize the jwtKey from the .env file
 func init() {
+	// Tests run without a .env file and do not need a signing key
+	if testing.Testing() {
+		return
+	}
+
 	// Load the .env file
 	err := godotenv.Load(".env") // Adjust the path if necessary
 	if err != nil {
@@ -45,21 +58,28 @@ type UserUsecase interface {
 	Verify(token string) error
 	FindUser(id string) (Domain.User, error)
 	InsertToken(username string, accessToken string, refreshToken string) error
-	GetAllUsers() ([]Domain.User, error)
+	GetAllUsers(role string) ([]Domain.User, error)
+	GetRoles() []Domain.RoleInfo
+	UpdateRoles(id string, input Domain.RoleAssignmentInput, changedBy primitive.ObjectID) (*Domain.User, error)
+	PurgeExpiredTokens() (int64, error)
+	ResendVerificationEmails() (int, int, error)
+	UpdateEmailPreferences(username string, input Domain.EmailPreferencesInput) error
 }
 
 type userUsecase struct {
 	userRepo        repository.UserRepository
 	logRepo         repository.LogRepository
-	emailService    *infrastructure.EmailService
+	outbox          OutboxUsecase
+	templates       EmailTemplateUsecase
 	passwordService *infrastructure.PasswordService
 }
 
-func NewUserUsecase(userRepo repository.UserRepository, logRepo repository.LogRepository, emailService *infrastructure.EmailService) UserUsecase {
+func NewUserUsecase(userRepo repository.UserRepository, logRepo repository.LogRepository, outbox OutboxUsecase, templates EmailTemplateUsecase) UserUsecase {
 	return &userUsecase{
 		userRepo:        userRepo,
 		logRepo:         logRepo,
-		emailService:    emailService,
+		outbox:          outbox,
+		templates:       templates,
 		passwordService: infrastructure.NewPasswordService(),
 	}
 }
@@ -67,6 +87,9 @@ func NewUserUsecase(userRepo repository.UserRepository, logRepo repository.LogRe
 const (
 	passwordMinLength = 8
 	passwordMaxLength = 20
+
+	// maxVerificationEmailAttempts bounds how often a failed verification email is retried.
+	maxVerificationEmailAttempts = 5
 )
 
 func (u *userUsecase) InsertToken(username string, accessToken string, refreshToken string) error {
@@ -99,6 +122,12 @@ func (u *userUsecase) Register(input Domain.RegisterInput) (*Domain.User, error)
 		return nil, errors.New("email already registered")
 	}
 
+	// Validate the locale emails are sent in
+	locale := normalizeLocale(input.Locale)
+	if locale != "" && !localePattern.MatchString(locale) {
+		return nil, errors.New("invalid locale")
+	}
+
 	// Validate password strength
 	if err := validatePasswordStrength(input.Password); err != nil {
 		return nil, err
@@ -119,13 +148,20 @@ func (u *userUsecase) Register(input Domain.RegisterInput) (*Domain.User, error)
 		Password:       string(hashedPassword),
 		ProfilePicture: input.ProfilePicture,
 		IsActive:       false, // Initially inactive
+		Locale:         locale,
 	}
 
-	// Set user role based on database state
-	if ok, err := u.userRepo.IsDbEmpty(); ok && err == nil {
-		user.Role = "admin"
-	} else {
-		user.Role = "user"
+	// Everyone registers as a borrower; staff roles are assigned by an admin.
+	// Until there is an admin, BOOTSTRAP_ADMIN_EMAIL registers as one.
+	user.Roles = []string{Domain.RoleBorrower}
+	if bootstrapEmail := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); bootstrapEmail != "" && strings.EqualFold(bootstrapEmail, input.Email) {
+		hasAdmin, err := u.userRepo.HasRole(Domain.RoleAdmin, primitive.NilObjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasAdmin {
+			user.Roles = append(user.Roles, Domain.RoleAdmin)
+		}
 	}
 
 	// Save user to repository
@@ -134,23 +170,84 @@ func (u *userUsecase) Register(input Domain.RegisterInput) (*Domain.User, error)
 		return nil, fmt.Errorf("failed to save user: %v", err)
 	}
 
+	// Queue the verification email; if it cannot be rendered or queued the
+	// account is kept and the resend-verification-emails job retries it
+	if err := u.sendVerificationEmail(*user); err != nil {
+		now := time.Now()
+		user.VerificationEmailFailedAt = &now
+		user.VerificationEmailAttempts = 1
+		update := bson.M{"verification_email_failed_at": now, "verification_email_attempts": 1}
+		if err := u.userRepo.Update(user.Username, update); err != nil {
+			return nil, fmt.Errorf("failed to record verification email failure: %v", err)
+		}
+	}
+
+	return user, nil
+}
+
+// sendVerificationEmail queues the welcome email with the account verification link.
+func (u *userUsecase) sendVerificationEmail(user Domain.User) error {
 	// Generate a verification token
-	newToken, err := infrastructure.GenerateResetToken(user.Username, user.Role, jwtKey)
+	newToken, err := infrastructure.GenerateResetToken(user.Username, user.Roles, jwtKey)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %v", err)
+	}
+
+	// Render the email in the user's language
+	message, err := u.templates.Render(Domain.EmailTemplateVerifyEmail, user.Locale, map[string]interface{}{
+		"Name":  user.Name,
+		"Token": newToken,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate verification token: %v", err)
+		return err
 	}
+	message.To = user.Email
 
-	// Construct the email body
-	subject := "Welcome to Our Service!"
-	body := fmt.Sprintf("Hi %s,\n\nWelcome to our platform! Please verify your account by clicking the link below:\n\nhttp://localhost:8080/users/verify-email/%s\n\nThank you!", input.Name, newToken)
+	// Queue verification email
+	_, err = u.outbox.Enqueue(message, Domain.EmailTemplateVerifyEmail)
+	if err != nil {
+		return fmt.Errorf("failed to queue welcome email: %v", err)
+	}
+	return nil
+}
 
-	// Send verification email
-	err = u.emailService.SendEmail(input.Email, subject, body)
+// ResendVerificationEmails retries the verification emails that could not be
+// queued at registration. It returns how many were queued and how many failed again.
+func (u *userUsecase) ResendVerificationEmails() (int, int, error) {
+	users, err := u.userRepo.FindFailedVerifications(maxVerificationEmailAttempts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send welcome email: %v", err)
+		return 0, 0, err
 	}
 
-	return user, nil
+	sent, failed := 0, 0
+	for _, user := range users {
+		update := bson.M{"verification_email_failed_at": nil, "verification_email_attempts": 0}
+		if err := u.sendVerificationEmail(user); err != nil {
+			update = bson.M{"verification_email_failed_at": time.Now(), "verification_email_attempts": user.VerificationEmailAttempts + 1}
+			failed++
+		} else {
+			sent++
+		}
+		if err := u.userRepo.Update(user.Username, update); err != nil {
+			return sent, failed, fmt.Errorf("failed to update verification email status: %v", err)
+		}
+	}
+	return sent, failed, nil
+}
+
+// UpdateEmailPreferences records whether the user wants non-essential emails
+// such as payment reminders. Overdue notices and account emails are always sent.
+func (u *userUsecase) UpdateEmailPreferences(username string, input Domain.EmailPreferencesInput) error {
+	err := u.userRepo.Update(username, bson.M{"opt_out_non_essential": input.OptOutNonEssential})
+	if err != nil {
+		return fmt.Errorf("failed to update email preferences: %v", err)
+	}
+	return nil
+}
+
+// PurgeExpiredTokens deletes the access tokens that have expired or were revoked at logout.
+func (u *userUsecase) PurgeExpiredTokens() (int64, error) {
+	return u.userRepo.DeleteExpiredTokens(time.Now())
 }
 
 func (u *userUsecase) UpdatePassword(username string, newPassword string) error {
@@ -225,7 +322,7 @@ func (u *userUsecase) Login(c *gin.Context, LoginUser *Domain.LoginInput) (strin
 		return "", "", errors.New("invalid username or password")
 	}
 
-	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role)
+	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Roles)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate access token: %v", err)
 	}
@@ -276,7 +373,7 @@ func (u *userUsecase) ForgotPassword(c *gin.Context, email string) (string, erro
 		return "", errors.New("user not found")
 	}
 
-	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role)
+	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Roles)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate access token: %v", err)
 	}
@@ -293,23 +390,18 @@ func (u *userUsecase) ForgotPassword(c *gin.Context, email string) (string, erro
 		return "", fmt.Errorf("failed to store tokens: %v", err)
 	}
 
-	subject := "Password Reset Request"
-	body := fmt.Sprintf(`
-	Hi %s,
-
-	It seems like you requested a password reset. No worries, it happens to the best of us! You can reset your password by clicking the link below:
-
-	<a href="http://localhost:8080/users/password-reset/%s">Reset Your Password</a>
-
-	If you did not request a password reset, please ignore this email.
-
-Best regards,
-	Your Support Team
-	`, user.Name, accessToken)
+	message, err := u.templates.Render(Domain.EmailTemplatePasswordReset, user.Locale, map[string]interface{}{
+		"Name":  user.Name,
+		"Token": accessToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	message.To = user.Email
 
-	err = u.emailService.SendEmail(user.Email, subject, body)
+	_, err = u.outbox.Enqueue(message, Domain.EmailTemplatePasswordReset)
 	if err != nil {
-		return "", fmt.Errorf("failed to send reset email: %v", err)
+		return "", fmt.Errorf("failed to queue reset email: %v", err)
 	}
 
 	// Log password reset request
@@ -341,7 +433,7 @@ func (u *userUsecase) Reset(c *gin.Context, token string) (string, error) {
 		return "", errors.New("user not found")
 	}
 
-	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role)
+	accessToken, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Roles)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate access token: %v", err)
 	}
@@ -357,7 +449,7 @@ func (u *userUsecase) Reset(c *gin.Context, token string) (string, error) {
 	if err != nil {
 		return "", errors.New("user not found")
 	}
-	access_token, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Role)
+	access_token, err := infrastructure.GenerateJWT(user.ID.Hex(), user.Username, user.Roles)
 
 	if err != nil {
 		return "", fmt.Errorf("failed to generate access token: %v", err)
@@ -444,10 +536,84 @@ func validatePasswordStrength(password string) error {
 	return nil
 }
 
-func (uc *userUsecase) GetAllUsers() ([]Domain.User, error) {
-	users, err := uc.userRepo.GetAllUsers()
+func (uc *userUsecase) GetAllUsers(role string) ([]Domain.User, error) {
+	if role != "" && !containsString(Domain.Roles, role) {
+		return nil, ErrInvalidRole
+	}
+	users, err := uc.userRepo.GetAllUsers(role)
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
+
+// GetRoles lists every role with the permissions it grants.
+func (uc *userUsecase) GetRoles() []Domain.RoleInfo {
+	roles := make([]Domain.RoleInfo, 0, len(Domain.Roles))
+	for _, role := range Domain.Roles {
+		roles = append(roles, Domain.RoleInfo{Name: role, Permissions: Domain.RolePermissions[role]})
+	}
+	return roles
+}
+
+// UpdateRoles replaces the roles of a user. Every user keeps the borrower role,
+// admins cannot demote themselves and the last admin cannot be demoted. The
+// user's sessions are ended so that the new roles apply from their next login.
+func (uc *userUsecase) UpdateRoles(id string, input Domain.RoleAssignmentInput, changedBy primitive.ObjectID) (*Domain.User, error) {
+	userID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	users, err := uc.userRepo.FindByIDs([]primitive.ObjectID{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	user := users[0]
+
+	roles := []string{Domain.RoleBorrower}
+	for _, role := range input.Roles {
+		if !containsString(Domain.Roles, role) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+		}
+		if !containsString(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	if containsString(user.Roles, Domain.RoleAdmin) && !containsString(roles, Domain.RoleAdmin) {
+		if user.ID == changedBy {
+			return nil, ErrOwnAdminRole
+		}
+		hasAdmin, err := uc.userRepo.HasRole(Domain.RoleAdmin, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasAdmin {
+			return nil, ErrLastAdmin
+		}
+	}
+
+	if err := uc.userRepo.UpdateRoles(user.ID, roles); err != nil {
+		return nil, err
+	}
+	if err := uc.userRepo.ExpireUserTokens(user.Username); err != nil {
+		return nil, err
+	}
+
+	log := &Domain.LogEntry{
+		ID:        primitive.NewObjectID(),
+		LogType:   "Role Change",
+		Timestamp: time.Now(),
+		UserID:    changedBy.Hex(),
+		Message:   fmt.Sprintf("Roles of user %s changed from [%s] to [%s]", user.Username, strings.Join(user.Roles, ", "), strings.Join(roles, ", ")),
+	}
+	if err := uc.logRepo.Save(log); err != nil {
+		return nil, fmt.Errorf("failed to log Role Change: %v", err)
+	}
+
+	user.Roles = roles
+	return &user, nil
+}
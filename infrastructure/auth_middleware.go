@@ -4,6 +4,7 @@ import (
 	"Loan_Tracker/Domain"
 	"net/http"
 	"os"
+	"testing"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,11 @@ var jwtKey []byte
 
 // Initialize the jwtKey from the .env file
 func init() {
+	// Tests run without a .env file and do not need a signing key
+	if testing.Testing() {
+		return
+	}
+
 	// Load the .env file
 	err := godotenv.Load(".env") // Adjust the path if necessary
 	if err != nil {
@@ -29,11 +35,11 @@ func init() {
 	}
 }
 
-// Claims struct to include role
+// Claims struct to include roles
 type Claims struct {
-	ID       string `json:"id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	ID       string   `json:"id"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
 	jwt.StandardClaims
 }
 
@@ -77,17 +83,18 @@ func AuthMiddleware(tokenCollection *mongo.Collection) gin.HandlerFunc {
 		}
 		c.Set("userID", claims.ID)
 		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		c.Set("roles", claims.Roles)
 		c.Next()
 	}
 }
 
-// RoleMiddleware checks if the user has the required role.
-func AdminMiddleware() gin.HandlerFunc {
+// RequirePermission only lets the request through if one of the user's roles grants the permission.
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.AbortWithStatus(http.StatusForbidden)
+		roles := c.GetStringSlice("roles")
+		if !Domain.HasPermission(roles, permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Missing permission " + permission})
+			c.Abort()
 			return
 		}
 		c.Next()
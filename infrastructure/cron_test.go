@@ -0,0 +1,151 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{spec: "* * * * *"},
+		{spec: "*/15 0-6,22,23 1-31/2 * mon", wantErr: true},
+		{spec: "*/15 0-6,22,23 1-31/2 * 1"},
+		{spec: "0-30/10 * * * *"},
+		{spec: "5/20 * * * *"},
+		{spec: " 0 9 * * 1-5 "},
+		{spec: "0 0 * * 7"},
+		{spec: "@hourly"},
+		{spec: "@daily"},
+		{spec: "@weekly"},
+		{spec: "@monthly"},
+		{spec: "@every 10m"},
+		{spec: "@every 1s"},
+		{spec: "", wantErr: true},
+		{spec: "* * * *", wantErr: true},
+		{spec: "* * * * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "* 24 * * *", wantErr: true},
+		{spec: "* * 0 * *", wantErr: true},
+		{spec: "* * 32 * *", wantErr: true},
+		{spec: "* * * 0 *", wantErr: true},
+		{spec: "* * * 13 *", wantErr: true},
+		{spec: "* * * * 8", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "*/x * * * *", wantErr: true},
+		{spec: "30-10 * * * *", wantErr: true},
+		{spec: "1-x * * * *", wantErr: true},
+		{spec: "1,,2 * * * *", wantErr: true},
+		{spec: "@yearly", wantErr: true},
+		{spec: "@every", wantErr: true},
+		{spec: "@every 500ms", wantErr: true},
+		{spec: "@every ten minutes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCron(%q) returned no error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCron(%q) returned error: %v", tt.spec, err)
+			}
+			if got := schedule.String(); got != strings.TrimSpace(tt.spec) {
+				t.Errorf("String() = %q, want %q", got, strings.TrimSpace(tt.spec))
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "step within the hour", spec: "*/15 * * * *",
+			from: time.Date(2024, 3, 6, 10, 7, 30, 0, time.UTC), want: time.Date(2024, 3, 6, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after a matching time", spec: "@hourly",
+			from: time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC), want: time.Date(2024, 3, 6, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily across a month end", spec: "@daily",
+			from: time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC), want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "daily across a year end", spec: "30 6 * * *",
+			from: time.Date(2024, 12, 31, 7, 0, 0, 0, time.UTC), want: time.Date(2025, 1, 1, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "weekdays skip the weekend", spec: "0 9 * * 1-5",
+			from: time.Date(2024, 3, 8, 10, 0, 0, 0, time.UTC), want: time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekly runs on sunday", spec: "@weekly",
+			from: time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC), want: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "7 is sunday", spec: "0 0 * * 7",
+			from: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "monthly", spec: "@monthly",
+			from: time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC), want: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "restricted month", spec: "0 0 1 6 *",
+			from: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day", spec: "0 0 29 2 *",
+			from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "either restricted day field matches", spec: "0 0 13 * 5",
+			from: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 9, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month with any weekday", spec: "0 0 13 * *",
+			from: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2024, 9, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "list of minutes", spec: "5,35 * * * *",
+			from: time.Date(2024, 3, 6, 10, 5, 0, 0, time.UTC), want: time.Date(2024, 3, 6, 10, 35, 0, 0, time.UTC),
+		},
+		{
+			name: "never matches", spec: "0 0 30 2 *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: time.Time{},
+		},
+		{
+			name: "interval aligned to its multiple", spec: "@every 10m",
+			from: time.Date(2024, 3, 6, 10, 7, 30, 0, time.UTC), want: time.Date(2024, 3, 6, 10, 10, 0, 0, time.UTC),
+		},
+		{
+			name: "interval strictly after a multiple", spec: "@every 1h",
+			from: time.Date(2024, 3, 6, 10, 0, 0, 0, time.UTC), want: time.Date(2024, 3, 6, 11, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) returned error: %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) for %q = %s, want %s", tt.from, tt.spec, got, tt.want)
+			}
+		})
+	}
+}
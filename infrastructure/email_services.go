@@ -2,47 +2,193 @@ package infrastructure
 
 import (
 	"Loan_Tracker/Delivery/config"
+	"Loan_Tracker/Domain"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
 	"net/smtp"
+	"net/textproto"
 	"os"
-
-	"github.com/joho/godotenv"
+	"path/filepath"
+	"time"
 )
 
-type EmailService struct {
-	smtpConfig config.SMTPConfig
+// Mailer delivers a rendered email. Implementations are interchangeable and
+// chosen with MAIL_TRANSPORT; callers go through the email outbox rather than
+// using a Mailer directly.
+type Mailer interface {
+	Send(message Domain.EmailMessage) error
 }
 
-func NewEmailService() *EmailService {
-	return &EmailService{
-		smtpConfig: config.LoadSMTPConfig(),
-	}
+// smtpTimeout bounds a whole SMTP conversation so that an unresponsive server
+// cannot hold up the outbox worker.
+const smtpTimeout = 30 * time.Second
+
+type smtpMailer struct {
+	config config.SMTPConfig
 }
-func (es *EmailService) SendEmail(to, subject, body string) error {
-	err := godotenv.Load()
+
+// NewSMTPMailer sends email through an SMTP server, upgrading to TLS when the
+// server supports STARTTLS.
+func NewSMTPMailer(smtpConfig config.SMTPConfig) Mailer {
+	return &smtpMailer{config: smtpConfig}
+}
+
+func (m *smtpMailer) Send(message Domain.EmailMessage) error {
+	msg, err := BuildMIMEMessage(m.config.From, message)
 	if err != nil {
-		return fmt.Errorf("failed to load environment variables for email: %w", err)
-	}
-	es.smtpConfig.Host = os.Getenv("SMTP_HOST")
-	es.smtpConfig.Port = os.Getenv("SMTP_PORT")
-	es.smtpConfig.Username = os.Getenv("SMTP_USERNAME")
-	es.smtpConfig.Password = os.Getenv("SMTP_PASSWORD")
-	es.smtpConfig.From = os.Getenv("SMTP_FROM")
-	// SMTP server address format should include the hostname only, not the port
-	auth := smtp.PlainAuth("", es.smtpConfig.Username, es.smtpConfig.Password, es.smtpConfig.Host)
-
-	// The message should include the From header
-	msg := []byte("From: " + es.smtpConfig.From + "\r\n" +
-		"To: " + to + "\r\n" +
-		"Subject: " + subject + "\r\n" +
-		"MIME-Version: 1.0\r\n" +
-		"Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n" +
-		body + "\r\n")
+		return err
+	}
 
 	// SMTP server address format should include the hostname and port separated by a colon
-	err = smtp.SendMail(es.smtpConfig.Host+":"+es.smtpConfig.Port, auth, es.smtpConfig.From, []string{to}, msg)
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(m.config.Host, m.config.Port), smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to mail server: %w", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.config.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate with mail server: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.config.From); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	w, err := client.Data()
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return client.Quit()
+}
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer writes every email as an .eml file into dir instead of
+// sending it, which is convenient for development and tests.
+func NewFileMailer(dir string, from string) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (m *fileMailer) Send(message Domain.EmailMessage) error {
+	msg, err := BuildMIMEMessage(m.from, message)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail drop directory: %v", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	if err := os.WriteFile(filepath.Join(m.dir, name), msg, 0o644); err != nil {
+		return fmt.Errorf("failed to write email: %v", err)
+	}
+	return nil
+}
+
+type logMailer struct{}
+
+// NewLogMailer only logs the recipient and subject of every email.
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(message Domain.EmailMessage) error {
+	log.Printf("Email to %s: %s", message.To, message.Subject)
+	return nil
+}
+
+// BuildMIMEMessage encodes an email with its headers. Messages with an HTML
+// body are sent as multipart/alternative with the plain text part first, so
+// that clients fall back to it; others are sent as text/plain.
+func BuildMIMEMessage(from string, message Domain.EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if message.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, message.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=\"UTF-8\"", message.Text},
+		{"text/html; charset=\"UTF-8\"", message.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %v", err)
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return fmt.Errorf("failed to encode email body: %v", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to encode email body: %v", err)
+	}
 	return nil
 }
@@ -0,0 +1,114 @@
+package infrastructure
+
+import (
+	"Loan_Tracker/Domain"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// builtInTemplates holds the templates that ship with the service, laid out as
+// email_templates/<locale>/<name>.subject, .txt and optionally .html.
+//
+//go:embed email_templates
+var builtInTemplates embed.FS
+
+// BuiltInEmailTemplates returns every template that ships with the service.
+func BuiltInEmailTemplates() []Domain.EmailTemplate {
+	var templates []Domain.EmailTemplate
+	locales, _ := fs.ReadDir(builtInTemplates, "email_templates")
+	for _, locale := range locales {
+		for _, name := range Domain.EmailTemplateNames {
+			if tmpl, ok := BuiltInEmailTemplate(name, locale.Name()); ok {
+				templates = append(templates, tmpl)
+			}
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].Locale < templates[j].Locale
+	})
+	return templates
+}
+
+// BuiltInEmailTemplate returns the shipped variant of a template for a locale.
+func BuiltInEmailTemplate(name string, locale string) (Domain.EmailTemplate, bool) {
+	read := func(ext string) (string, bool) {
+		content, err := builtInTemplates.ReadFile(path.Join("email_templates", locale, name+ext))
+		return string(content), err == nil
+	}
+
+	subject, ok := read(".subject")
+	if !ok {
+		return Domain.EmailTemplate{}, false
+	}
+	text, ok := read(".txt")
+	if !ok {
+		return Domain.EmailTemplate{}, false
+	}
+	html, _ := read(".html")
+	return Domain.EmailTemplate{Name: name, Locale: locale, Subject: subject, Text: text, HTML: html, BuiltIn: true}, true
+}
+
+// ParseEmailTemplate checks that every part of a template compiles.
+func ParseEmailTemplate(tmpl Domain.EmailTemplate) error {
+	if _, err := texttemplate.New("subject").Parse(tmpl.Subject); err != nil {
+		return fmt.Errorf("invalid subject template: %v", err)
+	}
+	if _, err := texttemplate.New("text").Parse(tmpl.Text); err != nil {
+		return fmt.Errorf("invalid text template: %v", err)
+	}
+	if _, err := htmltemplate.New("html").Parse(tmpl.HTML); err != nil {
+		return fmt.Errorf("invalid html template: %v", err)
+	}
+	return nil
+}
+
+// RenderEmailTemplate renders a template with data. The HTML body is escaped
+// by html/template; the subject is folded onto a single line.
+func RenderEmailTemplate(tmpl Domain.EmailTemplate, data map[string]interface{}) (Domain.EmailMessage, error) {
+	var message Domain.EmailMessage
+
+	subject, err := renderText("subject", tmpl.Subject, data)
+	if err != nil {
+		return message, fmt.Errorf("failed to render subject of %s: %v", tmpl.Name, err)
+	}
+	message.Subject = strings.Join(strings.Fields(subject), " ")
+
+	if message.Text, err = renderText("text", tmpl.Text, data); err != nil {
+		return message, fmt.Errorf("failed to render text of %s: %v", tmpl.Name, err)
+	}
+
+	if tmpl.HTML != "" {
+		parsed, err := htmltemplate.New("html").Option("missingkey=error").Parse(tmpl.HTML)
+		if err != nil {
+			return message, fmt.Errorf("invalid html template: %v", err)
+		}
+		var html bytes.Buffer
+		if err := parsed.Execute(&html, data); err != nil {
+			return message, fmt.Errorf("failed to render html of %s: %v", tmpl.Name, err)
+		}
+		message.HTML = html.String()
+	}
+	return message, nil
+}
+
+func renderText(name string, source string, data map[string]interface{}) (string, error) {
+	parsed, err := texttemplate.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := parsed.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,195 @@
+package infrastructure
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RowWriter writes a table one row at a time so that exports can be streamed
+// to the client without holding them in memory.
+type RowWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVRowWriter writes rows as RFC 4180 CSV. Text cells that a spreadsheet
+// would evaluate as a formula are prefixed with a single quote.
+func NewCSVRowWriter(w io.Writer) RowWriter {
+	return &csvRowWriter{writer: csv.NewWriter(w)}
+}
+
+func (cw *csvRowWriter) WriteRow(values []string) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = csvEscapeFormula(value)
+	}
+	return cw.writer.Write(escaped)
+}
+
+// csvEscapeFormula neutralizes values such as borrower names or purposes that
+// start with a formula character. Numbers, including negative amounts, are kept as they are.
+func csvEscapeFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+func (cw *csvRowWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// xlsxRowWriter writes a single-sheet Office Open XML workbook. The static parts
+// of the package are written before the first row, then the rows of the sheet
+// are streamed into the zip archive as inline strings and numbers.
+type xlsxRowWriter struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+}
+
+// NewXLSXRowWriter writes rows as an Excel workbook with one sheet named "Loans".
+func NewXLSXRowWriter(w io.Writer) RowWriter {
+	return &xlsxRowWriter{archive: zip.NewWriter(w)}
+}
+
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Loans" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+func (xw *xlsxRowWriter) open() error {
+	for _, part := range xlsxParts {
+		w, err := xw.archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := xw.archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	xw.sheet = sheet
+	return err
+}
+
+func (xw *xlsxRowWriter) WriteRow(values []string) error {
+	if xw.sheet == nil {
+		if err := xw.open(); err != nil {
+			return fmt.Errorf("failed to start workbook: %v", err)
+		}
+	}
+	xw.rows++
+
+	if _, err := fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.rows); err != nil {
+		return err
+	}
+	for i, value := range values {
+		ref := xlsxColumn(i) + strconv.Itoa(xw.rows)
+		if xlsxIsNumber(value) {
+			if _, err := fmt.Fprintf(xw.sheet, `<c r="%s"><v>%s</v></c>`, ref, value); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(xw.sheet, []byte(value)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(xw.sheet, `</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(xw.sheet, `</row>`)
+	return err
+}
+
+func (xw *xlsxRowWriter) Close() error {
+	if xw.sheet == nil {
+		if err := xw.open(); err != nil {
+			return fmt.Errorf("failed to start workbook: %v", err)
+		}
+	}
+	if _, err := io.WriteString(xw.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return xw.archive.Close()
+}
+
+// xlsxColumn converts a zero-based column index to its letters, e.g. 27 -> "AB".
+func xlsxColumn(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxIsNumber reports whether a value can be stored as a number cell without
+// losing precision. Values with leading zeros are kept as text.
+func xlsxIsNumber(value string) bool {
+	digits := 0
+	for i, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '-' && i == 0, c == '.':
+		default:
+			return false
+		}
+	}
+	if digits == 0 || digits > 15 {
+		return false
+	}
+	unsigned := value
+	if unsigned[0] == '-' {
+		unsigned = unsigned[1:]
+	}
+	if len(unsigned) > 1 && unsigned[0] == '0' && unsigned[1] != '.' {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
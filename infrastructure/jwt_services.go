@@ -7,12 +7,12 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
-func GenerateJWT(id string, username string, role string) (string, error) {
+func GenerateJWT(id string, username string, roles []string) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 	claims := &Claims{
 		ID:       id,
 		Username: username,
-		Role:     role,
+		Roles:    roles,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
@@ -57,25 +57,25 @@ func GetIDFromToken(token *jwt.Token) (string, error) {
 	return claims.ID, nil
 }
 
-func GetRoleFromToken(token *jwt.Token) (string, error) {
+func GetRolesFromToken(token *jwt.Token) ([]string, error) {
 	claims, ok := token.Claims.(*Claims)
 	if !ok {
-		return "", jwt.ErrInvalidKey
+		return nil, jwt.ErrInvalidKey
 	}
-	return claims.Role, nil
+	return claims.Roles, nil
 }
 
 type ResetClaims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
 	jwt.StandardClaims
 }
 
-func GenerateResetToken(username string, role string, jwtKey []byte) (string, error) {
+func GenerateResetToken(username string, roles []string, jwtKey []byte) (string, error) {
 	expirationTime := time.Now().Add(10 * time.Minute)
 	claims := &ResetClaims{
 		Username: username,
-		Role:     role,
+		Roles:    roles,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expirationTime.Unix(),
 		},
@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"Loan_Tracker/Domain"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+type PayoutRequest struct {
+	LoanID         string
+	Amount         Domain.Money
+	Account        Domain.BankAccount
+	IdempotencyKey string // Repeating a request with the same key must not move the money twice
+}
+
+type PayoutResult struct {
+	Reference   string
+	CompletedAt time.Time
+}
+
+// PayoutProvider sends loan funds to a borrower's bank account.
+type PayoutProvider interface {
+	Name() string
+	Transfer(request PayoutRequest) (PayoutResult, error)
+}
+
+// FakePayoutProvider completes every transfer immediately without moving money.
+// It is meant for local development and testing.
+type FakePayoutProvider struct {
+	mu        sync.Mutex
+	completed map[string]PayoutResult // By idempotency key
+}
+
+func NewFakePayoutProvider() *FakePayoutProvider {
+	return &FakePayoutProvider{completed: map[string]PayoutResult{}}
+}
+
+func (p *FakePayoutProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakePayoutProvider) Transfer(request PayoutRequest) (PayoutResult, error) {
+	if request.Account.AccountNumber == "" {
+		return PayoutResult{}, errors.New("bank account number is required")
+	}
+	if !request.Amount.IsPositive() {
+		return PayoutResult{}, errors.New("payout amount must be greater than zero")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if result, ok := p.completed[request.IdempotencyKey]; ok && request.IdempotencyKey != "" {
+		return result, nil
+	}
+
+	reference := make([]byte, 8)
+	if _, err := rand.Read(reference); err != nil {
+		return PayoutResult{}, err
+	}
+
+	result := PayoutResult{
+		Reference:   "FAKE-" + hex.EncodeToString(reference),
+		CompletedAt: time.Now(),
+	}
+	if request.IdempotencyKey != "" {
+		p.completed[request.IdempotencyKey] = result
+	}
+	return result, nil
+}